@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackdelahunt/protoexplore/explore"
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails" // so error details can be printed from a status
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"io"
+	"os"
+	"strings"
+)
+
+// CallService is the service call can invoke, the admin service is left out on purpose
+const CallService = "ExploreService"
+
+// FindMethod looks up a method on the explore service from the compiled
+// descriptors, the name doesn't have to match case
+func FindMethod(name string) (protoreflect.MethodDescriptor, error) {
+	service := explore.File_explore_service_proto.Services().ByName(CallService)
+
+	methods := service.Methods()
+
+	for i := 0; i < methods.Len(); i++ {
+		if strings.EqualFold(string(methods.Get(i).Name()), name) {
+			return methods.Get(i), nil
+		}
+	}
+
+	return nil, usagef("%v has no method \"%v\", run call --list to see them all", CallService, name)
+}
+
+// newMessage makes the generated go type for a message so it works with the
+// normal grpc codec
+func newMessage(descriptor protoreflect.MessageDescriptor) (proto.Message, error) {
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(descriptor.FullName())
+	if err != nil {
+		return nil, err
+	}
+
+	return messageType.New().Interface(), nil
+}
+
+func CallCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("call")
+	list := flags.Bool("list", false, "list every method along with its request and response types")
+
+	rest, ctx, cancel, err := runner.parse(ctx, flags, args, -1)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	if *list {
+		return listMethods()
+	}
+
+	if len(rest) < 1 || len(rest) > 2 {
+		return usagef("expected a method and optionally a json request but got %v arguments", len(rest))
+	}
+
+	method, err := FindMethod(rest[0])
+	if err != nil {
+		return err
+	}
+
+	input := "{}"
+
+	if len(rest) == 2 {
+		input = rest[1]
+	}
+
+	if input == "-" {
+		bytes, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		input = string(bytes)
+	}
+
+	request, err := newMessage(method.Input())
+	if err != nil {
+		return err
+	}
+
+	err = protojson.Unmarshal([]byte(input), request)
+	if err != nil {
+		return usagef("request is not a valid %v: %v", method.Input().FullName(), err)
+	}
+
+	runner.Client()
+
+	fullMethod := fmt.Sprintf("/%v/%v", method.Parent().FullName(), method.Name())
+
+	if method.IsStreamingServer() {
+		err = callStream(ctx, runner, method, fullMethod, request)
+	} else {
+		err = callUnary(ctx, runner, method, fullMethod, request)
+	}
+
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			runner.Printer.writeJSON(st.Proto(), runner.Printer.Format != OutputJSONL)
+		}
+	}
+
+	return err
+}
+
+func callUnary(ctx context.Context, runner *Runner, method protoreflect.MethodDescriptor, fullMethod string, request proto.Message) error {
+	response, err := newMessage(method.Output())
+	if err != nil {
+		return err
+	}
+
+	err = runner.connection.Invoke(ctx, fullMethod, request, response)
+	if err != nil {
+		return err
+	}
+
+	return runner.Printer.writeJSON(response, runner.Printer.Format != OutputJSONL)
+}
+
+// callStream prints every message from the stream until the server ends it, the
+// timeout running out is treated as the end so --timeout is how long to watch for
+func callStream(ctx context.Context, runner *Runner, method protoreflect.MethodDescriptor, fullMethod string, request proto.Message) error {
+	description := &grpc.StreamDesc{StreamName: string(method.Name()), ServerStreams: true}
+
+	stream, err := runner.connection.NewStream(ctx, description, fullMethod)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.SendMsg(request); err != nil {
+		return err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		response, err := newMessage(method.Output())
+		if err != nil {
+			return err
+		}
+
+		err = stream.RecvMsg(response)
+		if errors.Is(err, io.EOF) || (err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := runner.Printer.writeJSON(response, runner.Printer.Format != OutputJSONL); err != nil {
+			return err
+		}
+	}
+}
+
+func listMethods() error {
+	service := explore.File_explore_service_proto.Services().ByName(CallService)
+
+	methods := service.Methods()
+
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+
+		output := string(method.Output().Name())
+		if method.IsStreamingServer() {
+			output = "stream " + output
+		}
+
+		fmt.Printf("%v(%v) returns (%v)\n", method.Name(), method.Input().Name(), output)
+	}
+
+	return nil
+}
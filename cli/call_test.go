@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindMethod(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		found  string
+	}{
+		{name: "exact", method: "PutDecision", found: "PutDecision"},
+		{name: "any case", method: "countlikedyou", found: "CountLikedYou"},
+		{name: "stream", method: "WatchLikes", found: "WatchLikes"},
+		{name: "admin", method: "ListDesirability"},
+		{name: "unknown", method: "Nope"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			method, err := FindMethod(test.method)
+
+			if test.found == "" {
+				var usage usageError
+				if !errors.As(err, &usage) {
+					t.Errorf("FindMethod(%v) error = %v, want a usage error", test.method, err)
+				}
+
+				return
+			}
+
+			if err != nil || string(method.Name()) != test.found {
+				t.Errorf("FindMethod(%v) = %v, %v, want %v", test.method, method, err, test.found)
+			}
+		})
+	}
+}
+
+// fakeExploreServer answers CountLikedYou and WatchLikes for whoever asks, a
+// user called missing doesnt exist
+type fakeExploreServer struct {
+	explore.UnimplementedExploreServiceServer
+}
+
+func (s *fakeExploreServer) CountLikedYou(ctx context.Context, request *explore.CountLikedYouRequest) (*explore.CountLikedYouResponse, error) {
+	if request.RecipientUserId == "missing" {
+		return nil, status.Error(codes.NotFound, "no user missing")
+	}
+
+	return &explore.CountLikedYouResponse{Count: uint64(len(request.RecipientUserId))}, nil
+}
+
+func (s *fakeExploreServer) WatchLikes(request *explore.WatchRequest, stream explore.ExploreService_WatchLikesServer) error {
+	for _, id := range []string{"a", "b"} {
+		err := stream.Send(&explore.LikeEvent{Liker: &explore.ListLikedYouResponse_Liker{ActorId: id}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// captureStdout runs f with stdout going to a file and returns what was written
+func captureStdout(t *testing.T, f func()) string {
+	file, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	stdout := os.Stdout
+	os.Stdout = file
+	defer func() { os.Stdout = stdout }()
+
+	f()
+
+	bytes, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(bytes)
+}
+
+func TestCallCommand(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	explore.RegisterExploreServiceServer(server, &fakeExploreServer{})
+
+	go server.Serve(listener)
+	defer server.Stop()
+
+	// call turns logging off unless asked for
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	tests := []struct {
+		name string
+		args []string
+		// output is each json message printed
+		output []string
+		code   int
+	}{
+		{
+			name:   "unary",
+			args:   []string{"countlikedyou", `{"recipient_user_id": "abc"}`},
+			output: []string{`{"count":"3"}`},
+		},
+		{
+			name:   "empty request",
+			args:   []string{"CountLikedYou"},
+			output: []string{`{"count":"0"}`},
+		},
+		{
+			name:   "stream until the server ends it",
+			args:   []string{"WatchLikes", `{"user_id": "abc"}`},
+			output: []string{`{"liker":{"actor_id":"a","unix_timestamp":"0","redacted":false}}`, `{"liker":{"actor_id":"b","unix_timestamp":"0","redacted":false}}`},
+		},
+		{
+			name:   "error from the server",
+			args:   []string{"CountLikedYou", `{"recipient_user_id": "missing"}`},
+			output: []string{`{"code":5,"message":"no user missing","details":[]}`},
+			code:   int(codes.NotFound),
+		},
+		{name: "not json", args: []string{"CountLikedYou", `{recipient_user_id}`}, code: ExitUsage},
+		{name: "wrong field", args: []string{"CountLikedYou", `{"user_id": "abc"}`}, code: ExitUsage},
+		{name: "unknown method", args: []string{"Nope"}, code: ExitUsage},
+		{name: "too many arguments", args: []string{"CountLikedYou", "{}", "{}"}, code: ExitUsage},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := &Runner{Options: DefaultOptions()}
+			defer runner.Close()
+
+			args := append([]string{"--addr", listener.Addr().String(), "--output", OutputJSONL}, test.args...)
+
+			var err error
+			output := captureStdout(t, func() {
+				err = CallCommand(context.Background(), runner, args)
+			})
+
+			if code := ExitCode(err); code != test.code {
+				t.Fatalf("CallCommand(%v) error = %v, want exit code %v", test.args, err, test.code)
+			}
+
+			var lines []string
+			for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+				if line != "" {
+					lines = append(lines, line)
+				}
+			}
+
+			if len(lines) != len(test.output) {
+				t.Fatalf("CallCommand(%v) printed %q, want %q", test.args, lines, test.output)
+			}
+
+			// compared as json so the spacing protojson picks doesnt matter
+			for i, line := range lines {
+				var got, want any
+				if err := json.Unmarshal([]byte(line), &got); err != nil {
+					t.Fatalf("printed %q which isnt json: %v", line, err)
+				}
+
+				if err := json.Unmarshal([]byte(test.output[i]), &want); err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("printed %v, want %v", line, test.output[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,712 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Options are the flags every command takes, they can go before or after the
+// command name
+type Options struct {
+	UserId       string
+	Profile      string
+	Address      string
+	AdminAddress string
+	Timeout      time.Duration
+	Verbose      bool
+	Output       string
+	TUI          bool
+}
+
+func DefaultOptions() *Options {
+	address := "localhost:50051"
+	if os.Getenv("SERVER_HOST") != "" || os.Getenv("SERVER_PORT") != "" {
+		address = fmt.Sprintf("%s:%s", os.Getenv("SERVER_HOST"), os.Getenv("SERVER_PORT"))
+	}
+
+	// the admin service is on its own port, on the same host unless it is moved
+	adminAddress := "localhost:50052"
+	if os.Getenv("ADMIN_HOST") != "" || os.Getenv("ADMIN_PORT") != "" {
+		host := os.Getenv("ADMIN_HOST")
+		if host == "" {
+			host = os.Getenv("SERVER_HOST")
+		}
+
+		adminAddress = fmt.Sprintf("%s:%s", host, os.Getenv("ADMIN_PORT"))
+	}
+
+	return &Options{
+		Address:      address,
+		AdminAddress: adminAddress,
+		Timeout:      10 * time.Second,
+		Output:       OutputText,
+	}
+}
+
+func (o *Options) Register(flags *flag.FlagSet) {
+	flags.StringVar(&o.UserId, "user", o.UserId, "user id to act as, defaults to the id in bin/client.id")
+	flags.StringVar(&o.Profile, "profile", o.Profile, "act as the user saved under this profile name")
+	flags.StringVar(&o.Address, "addr", o.Address, "server address as host:port")
+	flags.StringVar(&o.AdminAddress, "admin-addr", o.AdminAddress, "admin service address as host:port, only used by commands that need it")
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "how long to wait for each command, 0 waits forever")
+	flags.BoolVar(&o.Verbose, "v", o.Verbose, "log connection details to stderr")
+	flags.StringVar(&o.Output, "output", o.Output, "output format, one of text, table, json or jsonl")
+	flags.BoolVar(&o.TUI, "tui", o.TUI, "start the full screen swipe view instead of the menu, needs a terminal")
+}
+
+// Exit codes, anything that reaches the server exits with the gRPC status code
+// of the response (so 0 is success, 5 is NotFound, 8 is ResourceExhausted, ...)
+const (
+	ExitUsage = 64
+)
+
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+func usagef(format string, args ...any) error {
+	return usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// ExitCode turns the error from a command into the code the CLI exits with
+func ExitCode(err error) int {
+	// asking for help with -h is not a mistake, the usage was what was wanted
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+
+	var usage usageError
+	if errors.As(err, &usage) {
+		return ExitUsage
+	}
+
+	return int(status.Code(err))
+}
+
+// Runner is what commands use to get at the server, the connection is only made
+// once a command asks for it so the flags after the command name are used
+type Runner struct {
+	Options *Options
+	Printer *Printer
+
+	client     explore.ExploreServiceClient
+	connection *grpc.ClientConn
+
+	admin           explore.AdminServiceClient
+	adminConnection *grpc.ClientConn
+}
+
+func (r *Runner) Client() explore.ExploreServiceClient {
+	if r.client == nil {
+		r.client, r.connection, _ = NewClient(r.Options.Address)
+	}
+
+	return r.client
+}
+
+// Admin connects to the admin service, which listens on its own address
+func (r *Runner) Admin() explore.AdminServiceClient {
+	if r.admin == nil {
+		r.admin, r.adminConnection, _ = NewAdminClient(r.Options.AdminAddress)
+	}
+
+	return r.admin
+}
+
+// UserId is the user picked with the flags, see ResolveUserId
+func (r *Runner) UserId() (string, error) {
+	id, err := ResolveUserId(r.Options)
+	if err != nil {
+		return "", err
+	}
+
+	r.Options.UserId = id
+
+	return id, nil
+}
+
+// Flags starts a flag set for a command with every common option already added
+func (r *Runner) Flags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+
+	r.Options.Register(flags)
+
+	return flags
+}
+
+func (r *Runner) Close() {
+	if r.connection != nil {
+		r.connection.Close()
+	}
+
+	if r.adminConnection != nil {
+		r.adminConnection.Close()
+	}
+}
+
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	Run         func(ctx context.Context, runner *Runner, args []string) error
+}
+
+var Commands = []Command{
+	{
+		Name:        "likes list",
+		Usage:       "likes list [--all] [--page-token <token>]",
+		Description: "list everyone who liked you one page at a time, the next page token is printed to stderr",
+		Run:         LikesListCommand,
+	},
+	{
+		Name:        "likes new",
+		Usage:       "likes new",
+		Description: "list everyone who liked you that you haven't liked back",
+		Run:         LikesNewCommand,
+	},
+	{
+		Name:        "likes count",
+		Usage:       "likes count [--breakdown] [--since <unix timestamp>]",
+		Description: "count the likes you have received",
+		Run:         LikesCountCommand,
+	},
+	{
+		Name:        "decide",
+		Usage:       "decide <user id> like|pass [--idempotency-key <key>]",
+		Description: "like or pass on a user",
+		Run:         DecideCommand,
+	},
+	{
+		Name:        "matches",
+		Usage:       "matches",
+		Description: "list everyone you have matched with",
+		Run:         MatchesCommand,
+	},
+	{
+		Name:        "users",
+		Usage:       "users [--tier free|premium]",
+		Description: "list every user on the server to pick who to act as",
+		Run:         UsersCommand,
+	},
+	{
+		Name:        "profiles list",
+		Usage:       "profiles list",
+		Description: "list the saved profiles",
+		Run:         ProfilesListCommand,
+	},
+	{
+		Name:        "profiles save",
+		Usage:       "profiles save <name>",
+		Description: "save the user picked with --user or --profile under a name",
+		Run:         ProfilesSaveCommand,
+	},
+	{
+		Name:        "profiles delete",
+		Usage:       "profiles delete <name>",
+		Description: "delete a saved profile",
+		Run:         ProfilesDeleteCommand,
+	},
+	{
+		Name:        "call",
+		Usage:       "call <method> ['<json>'|-] [--list]",
+		Description: "call any ExploreService method with a json request, - reads the request from stdin",
+		Run:         CallCommand,
+	},
+	{
+		Name:        "simulate",
+		Usage:       "simulate [--users N] [--duration D] [--think D] [--like-probability P] [--seed S]",
+		Description: "run simulated users against the server and report throughput, latency and errors per rpc",
+		Run:         SimulateCommand,
+	},
+}
+
+// FindCommand matches the longest command name at the start of args
+func FindCommand(args []string) (Command, []string, bool) {
+	for _, words := range []int{2, 1} {
+		if len(args) < words {
+			continue
+		}
+
+		name := strings.Join(args[:words], " ")
+
+		for _, command := range Commands {
+			if command.Name == name {
+				return command, args[words:], true
+			}
+		}
+	}
+
+	return Command{}, nil, false
+}
+
+func PrintUsage(out io.Writer) {
+	fmt.Fprintln(out, "usage: cli [flags] <command> [command flags]")
+	fmt.Fprintln(out, "running with no command starts the interactive menu")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "commands:")
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	for _, command := range Commands {
+		fmt.Fprintf(writer, "  %v\t%v\n", command.Usage, command.Description)
+	}
+
+	writer.Flush()
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "flags (before or after the command):")
+
+	flags := flag.NewFlagSet("cli", flag.ContinueOnError)
+	flags.SetOutput(out)
+	DefaultOptions().Register(flags)
+	flags.PrintDefaults()
+}
+
+// RunCommand runs a single command and returns the code to exit with
+func RunCommand(options *Options, args []string) int {
+	if args[0] == "help" {
+		PrintUsage(os.Stdout)
+		return 0
+	}
+
+	command, rest, ok := FindCommand(args)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command \"%v\"\n\n", strings.Join(args, " "))
+		PrintUsage(os.Stderr)
+		return ExitUsage
+	}
+
+	runner := &Runner{Options: options}
+	defer runner.Close()
+
+	ctx := context.Background()
+
+	err := command.Run(ctx, runner, rest)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", status.Code(err), status.Convert(err).Message())
+		} else if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+
+	return ExitCode(err)
+}
+
+// ParseFlags parses flags that can be mixed in with the positional arguments,
+// the flag package stops at the first positional argument otherwise
+func ParseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		err := flags.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// start parses the flags and sets up everything a command needs to make a request
+// as the user it is acting as
+func (r *Runner) start(ctx context.Context, flags *flag.FlagSet, args []string, positional int) ([]string, context.Context, context.CancelFunc, error) {
+	rest, ctx, cancel, err := r.parse(ctx, flags, args, positional)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if _, err := r.UserId(); err != nil {
+		cancel()
+		return nil, nil, nil, err
+	}
+
+	return rest, ctx, cancel, nil
+}
+
+// parse is start for commands that don't act as any user, a positional count
+// below zero lets the command check its own arguments
+func (r *Runner) parse(ctx context.Context, flags *flag.FlagSet, args []string, positional int) ([]string, context.Context, context.CancelFunc, error) {
+	rest, err := ParseFlags(flags, args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if positional >= 0 && len(rest) != positional {
+		return nil, nil, nil, usagef("expected %v arguments but got %v", positional, len(rest))
+	}
+
+	r.Printer, err = NewPrinter(r.Options.Output)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !r.Options.Verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	cancel := func() {}
+	if r.Options.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, r.Options.Timeout)
+	}
+
+	return rest, ctx, cancel, nil
+}
+
+func LikesListCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("likes list")
+	all := flags.Bool("all", false, "keep going until every page has been listed, always on with --output jsonl")
+	pageToken := flags.String("page-token", "", "start from this page instead of the first")
+
+	_, ctx, cancel, err := runner.start(ctx, flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	var paginationToken *string
+	if *pageToken != "" {
+		paginationToken = pageToken
+	}
+
+	// jsonl prints every page as it comes in, json and table need every page
+	// before printing so they are all put into one response
+	streams := runner.Printer.Streams() || runner.Printer.Format == OutputText
+	combined := &explore.ListLikedYouResponse{}
+
+	for {
+		request := explore.ListLikedYouRequest{RecipientUserId: runner.Options.UserId, PaginationToken: paginationToken}
+
+		response, err := runner.Client().ListLikedYou(ctx, &request)
+		if err != nil {
+			return err
+		}
+
+		if streams {
+			if err := PrintLikers(runner.Printer, response, response.Likers); err != nil {
+				return err
+			}
+		}
+
+		combined.Likers = append(combined.Likers, response.Likers...)
+		combined.TotalCount = response.TotalCount
+		combined.NextPaginationToken = response.NextPaginationToken
+
+		if response.TotalCount != nil && runner.Printer.Format != OutputJSON {
+			fmt.Fprintf(os.Stderr, "%v likes in total, only premium users can see who they are\n", *response.TotalCount)
+		}
+
+		paginationToken = response.NextPaginationToken
+		if paginationToken == nil {
+			break
+		}
+
+		if !*all && !runner.Printer.Streams() {
+			if runner.Printer.Format != OutputJSON {
+				fmt.Fprintf(os.Stderr, "next page token: %v\n", *paginationToken)
+			}
+
+			break
+		}
+	}
+
+	if streams {
+		return nil
+	}
+
+	return PrintLikers(runner.Printer, combined, combined.Likers)
+}
+
+func LikesNewCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("likes new")
+
+	_, ctx, cancel, err := runner.start(ctx, flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	request := explore.ListLikedYouRequest{RecipientUserId: runner.Options.UserId}
+
+	response, err := runner.Client().ListNewLikedYou(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	return PrintLikers(runner.Printer, response, response.Likers)
+}
+
+func LikesCountCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("likes count")
+	breakdown := flags.Bool("breakdown", false, "also count unanswered likes, passes and matches")
+	since := flags.Int64("since", 0, "also count likes made after this unix timestamp")
+
+	_, ctx, cancel, err := runner.start(ctx, flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	request := explore.CountLikedYouRequest{RecipientUserId: runner.Options.UserId, IncludeBreakdown: *breakdown}
+	if *since > 0 {
+		sinceTimestamp := uint64(*since)
+		request.SinceUnixTimestamp = &sinceTimestamp
+	}
+
+	response, err := runner.Client().CountLikedYou(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	table := Table{Headers: []string{"count", "value"}}
+
+	if response.Breakdown == nil {
+		table.Add("total", response.Count)
+	} else {
+		table.Add("total", response.Breakdown.Total)
+		table.Add("unanswered", response.Breakdown.Unanswered)
+		table.Add("passed", response.Breakdown.Passed)
+		table.Add("matches", response.Breakdown.Matches)
+
+		if response.Breakdown.Since != nil {
+			table.Add("since", *response.Breakdown.Since)
+		}
+	}
+
+	return runner.Printer.Print(response, nil, table)
+}
+
+func DecideCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("decide")
+	idempotencyKey := flags.String("idempotency-key", "", "retrying with the same key won't record the decision twice")
+
+	rest, ctx, cancel, err := runner.start(ctx, flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	var liked bool
+
+	switch rest[1] {
+	case "like":
+		liked = true
+	case "pass":
+		liked = false
+	default:
+		return usagef("decision must be \"like\" or \"pass\" not \"%v\"", rest[1])
+	}
+
+	request := explore.PutDecisionRequest{
+		ActorUserId:     runner.Options.UserId,
+		RecipientUserId: rest[0],
+		LikedRecipient:  liked,
+	}
+
+	if *idempotencyKey != "" {
+		request.IdempotencyKey = idempotencyKey
+	}
+
+	response, err := runner.Client().PutDecision(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	if response.Replayed && runner.Printer.Format != OutputJSON && runner.Printer.Format != OutputJSONL {
+		fmt.Fprintln(os.Stderr, "this decision was already recorded with the same idempotency key")
+	}
+
+	table := Table{Headers: []string{"user", "decision", "mutual likes"}}
+	table.Add(rest[0], rest[1], response.MutualLikes)
+
+	return runner.Printer.Print(response, nil, table)
+}
+
+func MatchesCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("matches")
+
+	_, ctx, cancel, err := runner.start(ctx, flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	request := explore.ListMatchesRequest{UserId: runner.Options.UserId}
+
+	response, err := runner.Client().ListMatches(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	table := Table{Headers: []string{"user", "matched"}}
+	items := make([]proto.Message, len(response.Matches))
+
+	for i, match := range response.Matches {
+		table.Add(match.UserId, UnixTime(match.UnixTimestamp))
+		items[i] = match
+	}
+
+	return runner.Printer.Print(response, items, table)
+}
+
+func UsersCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("users")
+	tier := flags.String("tier", "", "only list users on this tier")
+
+	_, ctx, cancel, err := runner.parse(ctx, flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	profiles, err := LoadProfiles()
+	if err != nil {
+		return err
+	}
+
+	request := explore.ListUsersRequest{}
+	if *tier != "" {
+		request.Tier = tier
+	}
+
+	response, err := runner.Admin().ListUsers(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	table := Table{Headers: []string{"user", "tier", "created", "desirability", "profile"}}
+	items := make([]proto.Message, len(response.Users))
+
+	for i, user := range response.Users {
+		profile := profiles.NameOf(user.UserId)
+		if profile == "" {
+			profile = "-"
+		}
+
+		table.Add(user.UserId, user.Tier, UnixTime(user.UnixTimestamp), fmt.Sprintf("%.0f", user.Desirability), profile)
+		items[i] = user
+	}
+
+	return runner.Printer.Print(response, items, table)
+}
+
+func ProfilesListCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("profiles list")
+
+	_, _, cancel, err := runner.parse(ctx, flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	profiles, err := LoadProfiles()
+	if err != nil {
+		return err
+	}
+
+	table := Table{Headers: []string{"profile", "user"}}
+	for _, name := range profiles.Names() {
+		table.Add(name, profiles.Users[name])
+	}
+
+	// profiles aren't from the server so there is no message to print as json
+	return runner.Printer.PrintValue(profiles, table)
+}
+
+func ProfilesSaveCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("profiles save")
+
+	rest, _, cancel, err := runner.start(ctx, flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	profiles, err := LoadProfiles()
+	if err != nil {
+		return err
+	}
+
+	profiles.Users[rest[0]] = runner.Options.UserId
+
+	if err := profiles.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "saved %v as profile \"%v\"\n", runner.Options.UserId, rest[0])
+
+	return nil
+}
+
+func ProfilesDeleteCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("profiles delete")
+
+	rest, _, cancel, err := runner.parse(ctx, flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	profiles, err := LoadProfiles()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := profiles.Users[rest[0]]; !ok {
+		return usagef("no profile named \"%v\"", rest[0])
+	}
+
+	delete(profiles.Users, rest[0])
+
+	return profiles.Save()
+}
+
+// PrintLikers prints the likers from a response, redacted likers have no id
+func PrintLikers(printer *Printer, response proto.Message, likers []*explore.ListLikedYouResponse_Liker) error {
+	table := Table{Headers: []string{"user", "liked"}}
+	items := make([]proto.Message, len(likers))
+
+	for i, liker := range likers {
+		id := liker.ActorId
+		if liker.Redacted {
+			id = "redacted"
+		}
+
+		table.Add(id, UnixTime(liker.UnixTimestamp))
+		items[i] = liker
+	}
+
+	return printer.Print(response, items, table)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestFindCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		found string
+		rest  []string
+	}{
+		{name: "one word", args: []string{"matches"}, found: "matches", rest: []string{}},
+		{name: "two words", args: []string{"likes", "count"}, found: "likes count", rest: []string{}},
+		{name: "two words with arguments", args: []string{"likes", "list", "--all"}, found: "likes list", rest: []string{"--all"}},
+		{name: "one word with arguments", args: []string{"decide", "abc", "like"}, found: "decide", rest: []string{"abc", "like"}},
+		{name: "argument that looks like a second word", args: []string{"call", "list"}, found: "call", rest: []string{"list"}},
+		{name: "first word of a two word command", args: []string{"likes"}},
+		{name: "unknown", args: []string{"nope"}},
+		{name: "unknown second word", args: []string{"likes", "nope"}},
+		{name: "nothing", args: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			command, rest, ok := FindCommand(test.args)
+
+			if ok != (test.found != "") {
+				t.Fatalf("FindCommand(%v) found = %v, want %v", test.args, ok, test.found != "")
+			}
+
+			if command.Name != test.found {
+				t.Errorf("FindCommand(%v) = %v, want %v", test.args, command.Name, test.found)
+			}
+
+			if ok && !slices.Equal(rest, test.rest) {
+				t.Errorf("FindCommand(%v) rest = %v, want %v", test.args, rest, test.rest)
+			}
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		all        bool
+		token      string
+		wantErr    bool
+	}{
+		{name: "nothing", args: []string{}},
+		{name: "only flags", args: []string{"--all", "--page-token", "x"}, all: true, token: "x"},
+		{name: "only positional", args: []string{"a", "b"}, positional: []string{"a", "b"}},
+		{name: "flags first", args: []string{"--all", "a", "b"}, positional: []string{"a", "b"}, all: true},
+		{name: "flags last", args: []string{"a", "b", "--page-token=x"}, positional: []string{"a", "b"}, token: "x"},
+		{name: "flags in between", args: []string{"a", "--all", "b", "--page-token", "x"}, positional: []string{"a", "b"}, all: true, token: "x"},
+		{name: "unknown flag", args: []string{"a", "--nope"}, wantErr: true},
+		{name: "missing flag value", args: []string{"a", "--page-token"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags.SetOutput(io.Discard)
+
+			all := flags.Bool("all", false, "")
+			token := flags.String("page-token", "", "")
+
+			positional, err := ParseFlags(flags, test.args)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseFlags(%v) error = %v, want error %v", test.args, err, test.wantErr)
+			}
+
+			if test.wantErr {
+				return
+			}
+
+			if !slices.Equal(positional, test.positional) {
+				t.Errorf("ParseFlags(%v) = %v, want %v", test.args, positional, test.positional)
+			}
+
+			if *all != test.all || *token != test.token {
+				t.Errorf("ParseFlags(%v) set all=%v token=%v, want all=%v token=%v", test.args, *all, *token, test.all, test.token)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "success", err: nil, code: 0},
+		{name: "usage", err: usagef("bad"), code: ExitUsage},
+		{name: "wrapped usage", err: fmt.Errorf("decide: %w", usagef("bad")), code: ExitUsage},
+		{name: "help", err: flag.ErrHelp, code: 0},
+		{name: "wrapped help", err: fmt.Errorf("decide: %w", flag.ErrHelp), code: 0},
+		{name: "not found", err: status.Error(codes.NotFound, "no user"), code: 5},
+		{name: "resource exhausted", err: status.Error(codes.ResourceExhausted, "slow down"), code: 8},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "no server"), code: 14},
+		{name: "not from grpc", err: errors.New("broken"), code: int(codes.Unknown)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if code := ExitCode(test.err); code != test.code {
+				t.Errorf("ExitCode(%v) = %v, want %v", test.err, code, test.code)
+			}
+		})
+	}
+}
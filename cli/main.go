@@ -3,14 +3,19 @@ package main
 import (
 	"bufio"
 	"context"
+	"flag"
 	"fmt"
 	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"log"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var GLobalClientID string
@@ -26,9 +31,7 @@ func ReadClientId() (string, error) {
 	return strings.Trim(string(bytes), " \n\r\t"), nil
 }
 
-func NewClient(host string, port string) (explore.ExploreServiceClient, *grpc.ClientConn, error) {
-	url := fmt.Sprintf("%s:%s", host, port)
-
+func NewClient(url string) (explore.ExploreServiceClient, *grpc.ClientConn, error) {
 	var opts []grpc.DialOption
 	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 
@@ -44,6 +47,55 @@ func NewClient(host string, port string) (explore.ExploreServiceClient, *grpc.Cl
 	return client, connection, nil
 }
 
+func NewAdminClient(url string) (explore.AdminServiceClient, *grpc.ClientConn, error) {
+	var opts []grpc.DialOption
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+	connection, err := grpc.NewClient(url, opts...)
+	if err != nil {
+		log.Fatalf("failed to create connection to %v: %v", url, err)
+	}
+
+	admin := explore.NewAdminServiceClient(connection)
+
+	log.Printf("created admin client connected to %v", url)
+
+	return admin, connection, nil
+}
+
+// IsQuotaExceeded tells running out of likes for the day apart from being rate
+// limited, both are ResourceExhausted but only the quota error has a QuotaFailure
+func IsQuotaExceeded(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return false
+	}
+
+	for _, detail := range st.Details() {
+		if _, ok := detail.(*errdetails.QuotaFailure); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RateLimitedFor is how long to wait if the error is from the rate limiter
+func RateLimitedFor(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return 0, false
+	}
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.RetryDelay.AsDuration(), true
+		}
+	}
+
+	return 0, false
+}
+
 func EveryLikeListMenuOption(ctx context.Context, client explore.ExploreServiceClient, scanner *bufio.Scanner) error {
 	var paginationToken *string = nil
 
@@ -56,7 +108,16 @@ func EveryLikeListMenuOption(ctx context.Context, client explore.ExploreServiceC
 		}
 
 		for _, liker := range response.GetLikers() {
-			fmt.Printf("%v liked you at\n", liker.ActorId)
+			if liker.Redacted {
+				fmt.Printf("someone liked you on %v\n", time.Unix(int64(liker.UnixTimestamp), 0).UTC().Format(time.DateOnly))
+				continue
+			}
+
+			fmt.Printf("%v liked you %v\n", liker.ActorId, RelativeTime(UnixTime(liker.UnixTimestamp), time.Now()))
+		}
+
+		if response.TotalCount != nil {
+			fmt.Printf("%v people liked you, go premium to see who they are\n", *response.TotalCount)
 		}
 
 		paginationToken = response.NextPaginationToken
@@ -82,14 +143,23 @@ func NewLikeListMenuOption(ctx context.Context, client explore.ExploreServiceCli
 	fmt.Printf("%v people liked you without a like back\n", len(response.GetLikers()))
 
 	for _, liker := range response.GetLikers() {
+		if liker.Redacted {
+			fmt.Printf("someone liked you on %v\n", time.Unix(int64(liker.UnixTimestamp), 0).UTC().Format(time.DateOnly))
+			continue
+		}
+
 		fmt.Printf("%v liked you\n", liker.ActorId)
 	}
 
+	if response.TotalCount != nil {
+		fmt.Println("go premium to see who they are")
+	}
+
 	return nil
 }
 
 func CountAllLikesMenuOption(ctx context.Context, client explore.ExploreServiceClient) error {
-	request := explore.CountLikedYouRequest{RecipientUserId: GLobalClientID}
+	request := explore.CountLikedYouRequest{RecipientUserId: GLobalClientID, IncludeBreakdown: true}
 
 	response, err := client.CountLikedYou(ctx, &request)
 	if err != nil {
@@ -98,11 +168,16 @@ func CountAllLikesMenuOption(ctx context.Context, client explore.ExploreServiceC
 
 	fmt.Printf("You received %v likes!\n", response.Count)
 
+	if breakdown := response.Breakdown; breakdown != nil {
+		fmt.Printf("%v waiting on you, %v passed and %v matches\n", breakdown.Unanswered, breakdown.Passed, breakdown.Matches)
+	}
+
 	return nil
 }
 
 func MatchFromLikeListMenuOption(ctx context.Context, client explore.ExploreServiceClient, scanner *bufio.Scanner) error {
 	fmt.Printf("Use y/n/q to match, pass or stop matching. Some may have already passed but give them a second chance\n")
+	fmt.Printf("Use b to block someone or r to report and block them\n")
 
 	request := explore.ListLikedYouRequest{RecipientUserId: GLobalClientID, PaginationToken: nil}
 
@@ -111,6 +186,12 @@ func MatchFromLikeListMenuOption(ctx context.Context, client explore.ExploreServ
 		return err
 	}
 
+	// there is no one to match with if the server hid who they are
+	if listResponse.TotalCount != nil {
+		fmt.Printf("%v people liked you, go premium to see who they are and match with them\n", *listResponse.TotalCount)
+		return nil
+	}
+
 	i := 0 // manually handle i because bad input
 	likers := listResponse.GetLikers()
 
@@ -119,7 +200,7 @@ loop:
 		liker := likers[i]
 
 		fmt.Printf("do you like %v?\n", liker.ActorId)
-		input := StringInputWithPrompt(scanner, "y/n/q > ")
+		input := StringInputWithPrompt(scanner, "y/n/b/r/q > ")
 
 		if len(input) != 1 {
 			fmt.Println("Just one character please!")
@@ -142,6 +223,17 @@ loop:
 			}
 
 			decisionResponse, err := client.PutDecision(ctx, &decisionRequest)
+			if IsQuotaExceeded(err) {
+				fmt.Println("You are out of likes for today, come back tomorrow!")
+				break loop
+			}
+
+			if wait, ok := RateLimitedFor(err); ok {
+				fmt.Printf("Slow down! try again in %v\n", max(wait.Round(time.Second), time.Second))
+				i -= 1
+				continue
+			}
+
 			if err != nil {
 				return err
 			}
@@ -150,11 +242,41 @@ loop:
 			if decisionResponse.MutualLikes {
 				fmt.Println("It's a match congrats!!")
 			}
+		case 'b':
+			i += 1
+
+			blockRequest := explore.BlockUserRequest{
+				ActorUserId:     GLobalClientID,
+				RecipientUserId: liker.ActorId,
+			}
+
+			_, err := client.BlockUser(ctx, &blockRequest)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("Blocked, you won't see each other again")
+		case 'r':
+			i += 1
+
+			reportRequest := explore.ReportUserRequest{
+				ActorUserId:     GLobalClientID,
+				RecipientUserId: liker.ActorId,
+				Reason:          StringInputWithPrompt(scanner, "why are you reporting them? > "),
+				Block:           true,
+			}
+
+			_, err := client.ReportUser(ctx, &reportRequest)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("Thanks for the report, they have been blocked")
 		case 'q':
 			fmt.Println("Back to main menu")
 			break loop
 		default:
-			fmt.Println("Incorrect input! only \"y\" \"n\" and \"q\" allowed")
+			fmt.Println("Incorrect input! only \"y\" \"n\" \"b\" \"r\" and \"q\" allowed")
 			continue
 		}
 	}
@@ -162,6 +284,223 @@ loop:
 	return nil
 }
 
+func ExploreMenuOption(ctx context.Context, client explore.ExploreServiceClient, scanner *bufio.Scanner) error {
+	fmt.Printf("Use y/n/q to like, pass or stop exploring\n")
+
+	var paginationToken *string = nil
+
+	for {
+		request := explore.ListCandidatesRequest{ActorUserId: GLobalClientID, PaginationToken: paginationToken}
+
+		response, err := client.ListCandidates(ctx, &request)
+		if err != nil {
+			return err
+		}
+
+		candidates := response.GetCandidates()
+		if len(candidates) == 0 {
+			fmt.Println("You have seen everyone for now, check back later")
+			return nil
+		}
+
+		i := 0 // manually handle i because bad input
+
+		for i < len(candidates) {
+			candidate := candidates[i]
+
+			fmt.Printf("do you like %v?\n", candidate.UserId)
+			input := StringInputWithPrompt(scanner, "y/n/q > ")
+
+			if len(input) != 1 {
+				fmt.Println("Just one character please!")
+				continue
+			}
+
+			liked := false
+
+			switch input[0] {
+			case 'y':
+				liked = true
+				fallthrough
+			case 'n':
+				i += 1
+
+				decisionRequest := explore.PutDecisionRequest{
+					ActorUserId:     GLobalClientID,
+					RecipientUserId: candidate.UserId,
+					LikedRecipient:  liked,
+				}
+
+				decisionResponse, err := client.PutDecision(ctx, &decisionRequest)
+				if IsQuotaExceeded(err) {
+					fmt.Println("You are out of likes for today, come back tomorrow!")
+					return nil
+				}
+
+				if wait, ok := RateLimitedFor(err); ok {
+					fmt.Printf("Slow down! try again in %v\n", max(wait.Round(time.Second), time.Second))
+					i -= 1
+					continue
+				}
+
+				if err != nil {
+					return err
+				}
+
+				if decisionResponse.MutualLikes {
+					fmt.Println("It's a match congrats!!")
+				}
+			case 'q':
+				fmt.Println("Back to main menu")
+				return nil
+			default:
+				fmt.Println("Incorrect input! only \"y\" \"n\" and \"q\" allowed")
+			}
+		}
+
+		paginationToken = response.NextPaginationToken
+		if paginationToken == nil {
+			fmt.Println("You have seen everyone for now, check back later")
+			return nil
+		}
+	}
+}
+
+func WatchMenuOption(ctx context.Context, client explore.ExploreServiceClient, scanner *bufio.Scanner) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	request := explore.WatchRequest{UserId: GLobalClientID}
+
+	likes, err := client.WatchLikes(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	matches, err := client.WatchMatches(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			event, err := likes.Recv()
+			if err != nil {
+				if status.Code(err) != codes.Canceled {
+					log.Printf("stopped watching likes: %v", err)
+				}
+
+				return
+			}
+
+			if event.Liker.Redacted {
+				fmt.Println("someone new liked you!")
+				continue
+			}
+
+			fmt.Printf("%v just liked you!\n", event.Liker.ActorId)
+		}
+	}()
+
+	go func() {
+		for {
+			event, err := matches.Recv()
+			if err != nil {
+				if status.Code(err) != codes.Canceled {
+					log.Printf("stopped watching matches: %v", err)
+				}
+
+				return
+			}
+
+			fmt.Printf("It's a match with %v congrats!!\n", event.UserId)
+		}
+	}()
+
+	_ = StringInputWithPrompt(scanner, "watching for new likes and matches, press enter to stop\n")
+
+	return nil
+}
+
+func QuotaMenuOption(ctx context.Context, client explore.ExploreServiceClient) error {
+	request := explore.GetQuotaRequest{UserId: GLobalClientID}
+
+	response, err := client.GetQuota(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	resetsIn := time.Until(time.Unix(int64(response.ResetUnixTimestamp), 0)).Round(time.Minute)
+
+	fmt.Printf("You are on the %v tier\n", response.Tier)
+	fmt.Printf("You have %v of %v likes left today, resets in %v\n", response.LikesRemaining, response.DailyLikes, resetsIn)
+
+	return nil
+}
+
+func SwitchUserMenuOption(ctx context.Context, admin explore.AdminServiceClient, scanner *bufio.Scanner) error {
+	profiles, err := LoadProfiles()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("You are acting as %v\n", GLobalClientID)
+
+	// the admin service might not be reachable, any id can still be typed in
+	response, err := admin.ListUsers(ctx, &explore.ListUsersRequest{})
+	if err != nil {
+		log.Printf("could not list users, you can still enter an id or profile: %v", err)
+		response = &explore.ListUsersResponse{}
+	}
+
+	for i, user := range response.Users {
+		line := fmt.Sprintf("%v) %v [%v]", i+1, user.UserId, user.Tier)
+
+		if name := profiles.NameOf(user.UserId); name != "" {
+			line += fmt.Sprintf(" (%v)", name)
+		}
+
+		if user.UserId == GLobalClientID {
+			line += " <- you"
+		}
+
+		fmt.Println(line)
+	}
+
+	if names := profiles.Names(); len(names) > 0 {
+		fmt.Printf("Profiles: %v\n", strings.Join(names, ", "))
+	}
+
+	input := StringInputWithPrompt(scanner, "pick a number, profile or user id (enter to cancel): ")
+	if input == "" {
+		return nil
+	}
+
+	id := input
+
+	if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(response.Users) {
+		id = response.Users[n-1].UserId
+	} else if profileId, ok := profiles.Users[input]; ok {
+		id = profileId
+	}
+
+	GLobalClientID = id
+	fmt.Printf("Now acting as %v\n", id)
+
+	if profiles.NameOf(id) != "" {
+		return nil
+	}
+
+	name := StringInputWithPrompt(scanner, "save as a profile? enter a name (enter to skip): ")
+	if name == "" {
+		return nil
+	}
+
+	profiles.Users[name] = id
+
+	return profiles.Save()
+}
+
 func StringInputWithPrompt(scanner *bufio.Scanner, prompt string) string {
 	fmt.Print(prompt)
 	scanner.Scan()
@@ -178,35 +517,76 @@ func IntInputWithPrompt(scanner *bufio.Scanner, prompt string) (int, bool) {
 func main() {
 	log.SetPrefix("[Client]: ")
 
-	/* Read Client ID file */
-	id, err := ReadClientId()
+	options := DefaultOptions()
+
+	flags := flag.NewFlagSet("cli", flag.ContinueOnError)
+	flags.Usage = func() { PrintUsage(os.Stderr) }
+	options.Register(flags)
+
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(ExitCode(err))
+	}
+
+	/* Run a single command and exit if one was given */
+	if flags.NArg() > 0 {
+		os.Exit(RunCommand(options, flags.Args()))
+	}
+
+	/* Pick the user from --user, --profile or the client id file */
+	id, err := ResolveUserId(options)
 	if err != nil {
-		log.Fatalf("failed to read client id %v", err)
+		log.Fatalf("failed to pick a user to act as: %v", err)
 	}
 
-	log.Printf("client id file found, now using [id=%v]", id)
+	log.Printf("now acting as [id=%v]", id)
 
 	// this is stored globally because unlike the server the client implementation
-	// is done for us so having it global is an easy way to access it from the client
+	// is done for us so having it global is an easy way to access it from the client,
+	// switching user from the menu just changes this
 	GLobalClientID = id
 
-	client, connection, err := NewClient(os.Getenv("SERVER_HOST"), os.Getenv("SERVER_PORT"))
+	client, connection, err := NewClient(options.Address)
 	if err != nil {
 		log.Fatalf("failed to create client instance: %v", err)
 	}
 
 	defer connection.Close()
 
+	admin, adminConnection, err := NewAdminClient(options.AdminAddress)
+	if err != nil {
+		log.Fatalf("failed to create admin client instance: %v", err)
+	}
+
+	defer adminConnection.Close()
+
+	/* Run the full screen view if asked for and there is a terminal to draw it on */
+	if options.TUI {
+		if CanRunTUI() {
+			err := RunTUI(context.Background(), client)
+			if err != nil {
+				log.Fatalf("uh oh! we may have broke something, try again later: %v", err)
+			}
+
+			return
+		}
+
+		log.Printf("not running in a terminal, using the menu instead")
+	}
+
 	/* Run interactive CLI in a REPL  */
 	scanner := bufio.NewScanner(os.Stdin)
 
 	for {
-		fmt.Println("\n--- Protoexplore ---")
+		fmt.Printf("\n--- Protoexplore (%v) ---\n", GLobalClientID)
 		fmt.Println("1) See everyone who liked you")
 		fmt.Println("2) See likes from people you haven't yet")
 		fmt.Println("3) Get your total likes")
 		fmt.Println("4) Match with people who liked you")
-		fmt.Println("5) Exit")
+		fmt.Println("5) Explore new people")
+		fmt.Println("6) See your daily likes left")
+		fmt.Println("7) Watch for new likes and matches")
+		fmt.Println("8) Switch user")
+		fmt.Println("9) Exit")
 
 		choice, ok := IntInputWithPrompt(scanner, "> ")
 		if !ok {
@@ -235,10 +615,30 @@ func main() {
 				log.Printf("uh oh! we may have broke something, try again later: %v", err)
 			}
 		case 5:
+			err := ExploreMenuOption(context.Background(), client, scanner)
+			if err != nil {
+				log.Printf("uh oh! we may have broke something, try again later: %v", err)
+			}
+		case 6:
+			err := QuotaMenuOption(context.Background(), client)
+			if err != nil {
+				log.Printf("uh oh! we may have broke something, try again later: %v", err)
+			}
+		case 7:
+			err := WatchMenuOption(context.Background(), client, scanner)
+			if err != nil {
+				log.Printf("uh oh! we may have broke something, try again later: %v", err)
+			}
+		case 8:
+			err := SwitchUserMenuOption(context.Background(), admin, scanner)
+			if err != nil {
+				log.Printf("uh oh! we may have broke something, try again later: %v", err)
+			}
+		case 9:
 			println("Come back soon! ...exiting")
 			os.Exit(0)
 		default:
-			fmt.Println("That option is not available, please choose between 1 and 5")
+			fmt.Println("That option is not available, please choose between 1 and 9")
 		}
 	}
 }
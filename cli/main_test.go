@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withDetails(code codes.Code, details ...protoadapt.MessageV1) error {
+	st, err := status.New(code, "exhausted").WithDetails(details...)
+	if err != nil {
+		panic(err)
+	}
+
+	return st.Err()
+}
+
+func TestQuotaAndRateLimitErrors(t *testing.T) {
+	quotaFailure := &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "likes"}}}
+	retryInfo := &errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)}
+
+	tests := []struct {
+		name        string
+		err         error
+		quota       bool
+		rateLimited bool
+		wait        time.Duration
+	}{
+		{name: "out of likes", err: withDetails(codes.ResourceExhausted, quotaFailure), quota: true},
+		{name: "rate limited", err: withDetails(codes.ResourceExhausted, retryInfo), rateLimited: true, wait: 1500 * time.Millisecond},
+		{name: "exhausted without details", err: status.Error(codes.ResourceExhausted, "exhausted")},
+		{name: "quota failure on another code", err: withDetails(codes.FailedPrecondition, quotaFailure)},
+		{name: "retry info on another code", err: withDetails(codes.Unavailable, retryInfo)},
+		{name: "not a status", err: errors.New("connection refused")},
+		{name: "no error", err: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if quota := IsQuotaExceeded(test.err); quota != test.quota {
+				t.Errorf("IsQuotaExceeded() = %v, want %v", quota, test.quota)
+			}
+
+			wait, rateLimited := RateLimitedFor(test.err)
+			if rateLimited != test.rateLimited || wait != test.wait {
+				t.Errorf("RateLimitedFor() = %v %v, want %v %v", wait, rateLimited, test.wait, test.rateLimited)
+			}
+		})
+	}
+}
+
+// fakeExploreClient answers ListCandidates from pages by pagination token and
+// PutDecision with the errors in decisionErrs in order, anything else panics
+type fakeExploreClient struct {
+	explore.ExploreServiceClient
+
+	pages        map[string]*explore.ListCandidatesResponse
+	decisionErrs []error
+
+	tokens    []string
+	decisions []*explore.PutDecisionRequest
+}
+
+func (c *fakeExploreClient) ListCandidates(ctx context.Context, in *explore.ListCandidatesRequest, opts ...grpc.CallOption) (*explore.ListCandidatesResponse, error) {
+	c.tokens = append(c.tokens, in.GetPaginationToken())
+	return c.pages[in.GetPaginationToken()], nil
+}
+
+func (c *fakeExploreClient) PutDecision(ctx context.Context, in *explore.PutDecisionRequest, opts ...grpc.CallOption) (*explore.PutDecisionResponse, error) {
+	c.decisions = append(c.decisions, in)
+
+	if len(c.decisionErrs) > 0 {
+		err := c.decisionErrs[0]
+		c.decisionErrs = c.decisionErrs[1:]
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &explore.PutDecisionResponse{}, nil
+}
+
+func candidatePage(token *string, ids ...string) *explore.ListCandidatesResponse {
+	response := &explore.ListCandidatesResponse{NextPaginationToken: token}
+	for _, id := range ids {
+		response.Candidates = append(response.Candidates, &explore.ListCandidatesResponse_Candidate{UserId: id})
+	}
+
+	return response
+}
+
+func TestExploreMenuOption(t *testing.T) {
+	next := "b"
+	pages := map[string]*explore.ListCandidatesResponse{
+		"":  candidatePage(&next, "a", "b"),
+		"b": candidatePage(nil, "c"),
+	}
+
+	quota := withDetails(codes.ResourceExhausted, &errdetails.QuotaFailure{})
+	rateLimited := withDetails(codes.ResourceExhausted, &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Millisecond)})
+
+	tests := []struct {
+		name         string
+		input        string
+		decisionErrs []error
+		// decided is each decision sent as recipient:liked
+		decided []string
+		tokens  []string
+	}{
+		{name: "every page", input: "y\nn\ny\n", decided: []string{"a:true", "b:false", "c:true"}, tokens: []string{"", "b"}},
+		{name: "quit", input: "y\nq\n", decided: []string{"a:true"}, tokens: []string{""}},
+		{name: "bad input asks again", input: "yes\nx\nn\nq\n", decided: []string{"a:false"}, tokens: []string{""}},
+		{name: "out of likes", input: "y\n", decisionErrs: []error{quota}, decided: []string{"a:true"}, tokens: []string{""}},
+		{
+			name:         "rate limited asks about the same person again",
+			input:        "y\ny\nq\n",
+			decisionErrs: []error{rateLimited},
+			decided:      []string{"a:true", "a:true"},
+			tokens:       []string{""},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fakeExploreClient{pages: pages, decisionErrs: test.decisionErrs}
+			scanner := bufio.NewScanner(strings.NewReader(test.input))
+
+			if err := ExploreMenuOption(context.Background(), client, scanner); err != nil {
+				t.Fatalf("ExploreMenuOption() error = %v", err)
+			}
+
+			var decided []string
+			for _, decision := range client.decisions {
+				decided = append(decided, fmt.Sprintf("%v:%v", decision.RecipientUserId, decision.LikedRecipient))
+			}
+
+			if !slices.Equal(decided, test.decided) {
+				t.Errorf("decided %v, want %v", decided, test.decided)
+			}
+
+			if !slices.Equal(client.tokens, test.tokens) {
+				t.Errorf("pages asked for %q, want %q", client.tokens, test.tokens)
+			}
+		})
+	}
+}
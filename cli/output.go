@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	OutputText  = "text"
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputJSONL = "jsonl"
+)
+
+var OutputFormats = []string{OutputText, OutputTable, OutputJSON, OutputJSONL}
+
+// Table is what a command prints in text and table mode. Cells can be anything
+// but time.Time is printed as a timestamp in text mode and how long ago it was in
+// table mode
+type Table struct {
+	Headers []string
+	Rows    [][]any
+}
+
+func (t *Table) Add(cells ...any) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Printer writes command output in whichever format was picked with --output
+//   - text: tab separated rows with no header, easy to use with cut or awk
+//   - table: aligned columns with a header and relative times, for reading
+//   - json: the whole response as one json document
+//   - jsonl: one json object per line, each item in a list gets its own line
+type Printer struct {
+	Format string
+	Out    io.Writer
+	Now    func() time.Time
+}
+
+func NewPrinter(format string) (*Printer, error) {
+	if !slices.Contains(OutputFormats, format) {
+		return nil, usagef("unknown output format \"%v\", must be one of %v", format, strings.Join(OutputFormats, ", "))
+	}
+
+	return &Printer{Format: format, Out: os.Stdout, Now: time.Now}, nil
+}
+
+// Streams is true when every page should be fetched and printed as it arrives
+func (p *Printer) Streams() bool {
+	return p.Format == OutputJSONL
+}
+
+// Print writes a single response, items are the messages inside it that jsonl
+// puts one per line. If items is nil jsonl prints the response on one line
+func (p *Printer) Print(response proto.Message, items []proto.Message, table Table) error {
+	switch p.Format {
+	case OutputJSON:
+		return p.writeJSON(response, true)
+	case OutputJSONL:
+		if items == nil {
+			return p.writeJSON(response, false)
+		}
+
+		for _, item := range items {
+			if err := p.writeJSON(item, false); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case OutputTable:
+		return p.writeTable(table)
+	default:
+		return p.writeText(table)
+	}
+}
+
+// PrintValue is Print for anything that isn't a response from the server, json
+// and jsonl use encoding/json instead
+func (p *Printer) PrintValue(value any, table Table) error {
+	switch p.Format {
+	case OutputJSON, OutputJSONL:
+		var encoded []byte
+		var err error
+
+		if p.Format == OutputJSON {
+			encoded, err = json.MarshalIndent(value, "", "  ")
+		} else {
+			encoded, err = json.Marshal(value)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(p.Out, string(encoded))
+		return err
+	case OutputTable:
+		return p.writeTable(table)
+	default:
+		return p.writeText(table)
+	}
+}
+
+func (p *Printer) writeJSON(message proto.Message, multiline bool) error {
+	options := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	if multiline {
+		options.Multiline = true
+		options.Indent = "  "
+	}
+
+	encoded, err := options.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(p.Out, string(encoded))
+	return err
+}
+
+func (p *Printer) writeText(table Table) error {
+	for _, row := range table.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = p.cell(cell)
+		}
+
+		if _, err := fmt.Fprintln(p.Out, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Printer) writeTable(table Table) error {
+	writer := tabwriter.NewWriter(p.Out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, strings.ToUpper(strings.Join(table.Headers, "\t")))
+
+	for _, row := range table.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = p.cell(cell)
+		}
+
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+
+	return writer.Flush()
+}
+
+func (p *Printer) cell(value any) string {
+	switch value := value.(type) {
+	case time.Time:
+		if p.Format == OutputTable {
+			return RelativeTime(value, p.Now())
+		}
+
+		return value.UTC().Format(time.RFC3339)
+	case *string:
+		if value == nil {
+			return "-"
+		}
+
+		return *value
+	case *uint64:
+		if value == nil {
+			return "-"
+		}
+
+		return fmt.Sprint(*value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// RelativeTime is how long ago t was, like "3 hours ago". Anything more than a
+// month ago is just shown as the date
+func RelativeTime(t time.Time, now time.Time) string {
+	ago := now.Sub(t)
+
+	if ago < 0 {
+		return t.UTC().Format(time.DateOnly)
+	}
+
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %v ago", unit)
+		}
+
+		return fmt.Sprintf("%v %vs ago", n, unit)
+	}
+
+	switch {
+	case ago < time.Minute:
+		return "just now"
+	case ago < time.Hour:
+		return plural(int(ago/time.Minute), "minute")
+	case ago < 24*time.Hour:
+		return plural(int(ago/time.Hour), "hour")
+	case ago < 30*24*time.Hour:
+		return plural(int(ago/(24*time.Hour)), "day")
+	default:
+		return t.UTC().Format(time.DateOnly)
+	}
+}
+
+func UnixTime(unixTimestamp uint64) time.Time {
+	return time.Unix(int64(unixTimestamp), 0)
+}
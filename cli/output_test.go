@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/protobuf/proto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		ago  time.Duration
+		want string
+	}{
+		{name: "now", ago: 0, want: "just now"},
+		{name: "seconds", ago: 59 * time.Second, want: "just now"},
+		{name: "one minute", ago: time.Minute, want: "1 minute ago"},
+		{name: "minutes", ago: 59 * time.Minute, want: "59 minutes ago"},
+		{name: "one hour", ago: time.Hour, want: "1 hour ago"},
+		{name: "hours", ago: 23*time.Hour + 59*time.Minute, want: "23 hours ago"},
+		{name: "one day", ago: 24 * time.Hour, want: "1 day ago"},
+		{name: "days", ago: 29 * 24 * time.Hour, want: "29 days ago"},
+		{name: "a month", ago: 30 * 24 * time.Hour, want: "2024-05-16"},
+		{name: "in the future", ago: -time.Hour, want: "2024-06-15"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := RelativeTime(now.Add(-test.ago), now); got != test.want {
+				t.Errorf("RelativeTime(%v ago) = %v, want %v", test.ago, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTableAdd(t *testing.T) {
+	table := Table{Headers: []string{"a", "b"}}
+	table.Add("1", 2)
+	table.Add("3", 4)
+
+	if len(table.Rows) != 2 || table.Rows[0][0] != "1" || table.Rows[1][1] != 4 {
+		t.Errorf("Rows = %v, want each Add as its own row", table.Rows)
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	token := "next"
+
+	table := Table{Headers: []string{"id", "liked", "token", "count"}}
+	table.Add("u1", now.Add(-2*time.Hour), &token, (*uint64)(nil))
+	table.Add("u2", now.Add(-90*time.Second), (*string)(nil), proto.Uint64(3))
+
+	response := &explore.ListLikedYouResponse{
+		Likers: []*explore.ListLikedYouResponse_Liker{
+			{ActorId: "u1", UnixTimestamp: 1},
+			{ActorId: "u2", UnixTimestamp: 2},
+		},
+	}
+
+	items := []proto.Message{response.Likers[0], response.Likers[1]}
+
+	tests := []struct {
+		format string
+		items  []proto.Message
+		want   string
+	}{
+		{
+			format: OutputText,
+			items:  items,
+			want:   "u1\t2024-06-15T10:00:00Z\tnext\t-\nu2\t2024-06-15T11:58:30Z\t-\t3\n",
+		},
+		{
+			format: OutputTable,
+			items:  items,
+			want: "ID  LIKED         TOKEN  COUNT\n" +
+				"u1  2 hours ago   next   -\n" +
+				"u2  1 minute ago  -      3\n",
+		},
+		{
+			format: OutputJSONL,
+			items:  items,
+			want: `{"actor_id":"u1","unix_timestamp":"1","redacted":false}` + "\n" +
+				`{"actor_id":"u2","unix_timestamp":"2","redacted":false}` + "\n",
+		},
+		{
+			format: OutputJSONL,
+			items:  nil,
+			want:   `{"likers":[{"actor_id":"u1","unix_timestamp":"1","redacted":false},{"actor_id":"u2","unix_timestamp":"2","redacted":false}]}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var out bytes.Buffer
+			printer := &Printer{Format: test.format, Out: &out, Now: func() time.Time { return now }}
+
+			if err := printer.Print(response, test.items, table); err != nil {
+				t.Fatalf("Print() error = %v", err)
+			}
+
+			// protojson adds random spaces so its output can't be relied on byte for byte
+			got := out.String()
+			if test.format == OutputJSONL {
+				got = strings.ReplaceAll(got, " ", "")
+			}
+
+			if got != test.want {
+				t.Errorf("Print() =\n%v\nwant\n%v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPrinterJSON(t *testing.T) {
+	var out bytes.Buffer
+	printer := &Printer{Format: OutputJSON, Out: &out, Now: time.Now}
+
+	err := printer.Print(&explore.ListLikedYouResponse{}, nil, Table{})
+	if err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	// unset fields are still there, optional ones are left out
+	if got := strings.Join(strings.Fields(out.String()), ""); got != `{"likers":[]}` {
+		t.Errorf("Print() = %q, want just the empty likers", out.String())
+	}
+
+	if strings.Count(out.String(), "\n") < 2 {
+		t.Errorf("Print() = %q, want it indented over multiple lines", out.String())
+	}
+}
+
+func TestPrintValue(t *testing.T) {
+	value := []struct {
+		Name string `json:"name"`
+	}{{Name: "a"}, {Name: "b"}}
+
+	table := Table{Headers: []string{"name"}}
+	table.Add("a")
+	table.Add("b")
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: OutputText, want: "a\nb\n"},
+		{format: OutputTable, want: "NAME\na\nb\n"},
+		{format: OutputJSON, want: "[\n  {\n    \"name\": \"a\"\n  },\n  {\n    \"name\": \"b\"\n  }\n]\n"},
+		{format: OutputJSONL, want: `[{"name":"a"},{"name":"b"}]` + "\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var out bytes.Buffer
+			printer := &Printer{Format: test.format, Out: &out, Now: time.Now}
+
+			if err := printer.PrintValue(value, table); err != nil {
+				t.Fatalf("PrintValue() error = %v", err)
+			}
+
+			if out.String() != test.want {
+				t.Errorf("PrintValue() =\n%q\nwant\n%q", out.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestNewPrinter(t *testing.T) {
+	for _, format := range OutputFormats {
+		if _, err := NewPrinter(format); err != nil {
+			t.Errorf("NewPrinter(%v) error = %v", format, err)
+		}
+	}
+
+	if _, err := NewPrinter("yaml"); ExitCode(err) != ExitUsage {
+		t.Errorf("NewPrinter(yaml) error = %v, want a usage error", err)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Profiles are names for user ids so switching between users doesn't need the
+// whole id typed out. They are kept in a local json file, bin/profiles.json by
+// default or wherever CLI_PROFILES points
+type Profiles struct {
+	Users map[string]string `json:"users"`
+
+	path string
+}
+
+func ProfilesPath() string {
+	if path := os.Getenv("CLI_PROFILES"); path != "" {
+		return path
+	}
+
+	return "bin/profiles.json"
+}
+
+// LoadProfiles reads the profiles file, if there isn't one yet there are no profiles
+func LoadProfiles() (*Profiles, error) {
+	profiles := &Profiles{Users: map[string]string{}, path: ProfilesPath()}
+
+	bytes, err := os.ReadFile(profiles.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return profiles, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(bytes, profiles); err != nil {
+		return nil, fmt.Errorf("error reading profiles from %v: %v", profiles.path, err)
+	}
+
+	if profiles.Users == nil {
+		profiles.Users = map[string]string{}
+	}
+
+	return profiles, nil
+}
+
+func (p *Profiles) Save() error {
+	bytes, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(p.path, append(bytes, '\n'), 0644)
+}
+
+// Names is every profile name in order
+func (p *Profiles) Names() []string {
+	names := make([]string, 0, len(p.Users))
+	for name := range p.Users {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// NameOf finds the profile for a user id, empty if there isn't one
+func (p *Profiles) NameOf(userId string) string {
+	for _, name := range p.Names() {
+		if p.Users[name] == userId {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// ResolveUserId picks who the CLI acts as, --user wins over --profile and
+// without either the id the server wrote to bin/client.id is used
+func ResolveUserId(options *Options) (string, error) {
+	if options.UserId != "" {
+		return options.UserId, nil
+	}
+
+	if options.Profile != "" {
+		profiles, err := LoadProfiles()
+		if err != nil {
+			return "", err
+		}
+
+		id, ok := profiles.Users[options.Profile]
+		if !ok {
+			return "", usagef("no profile named \"%v\" in %v", options.Profile, profiles.path)
+		}
+
+		return id, nil
+	}
+
+	id, err := ReadClientId()
+	if err != nil {
+		return "", usagef("no --user or --profile given and could not read client id file: %v", err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	tests := []struct {
+		name string
+		// file is what is in the profiles file, empty if there isnt one
+		file  string
+		users map[string]string
+		fails bool
+	}{
+		{name: "no file", file: "", users: map[string]string{}},
+		{name: "profiles", file: `{"users": {"alice": "a", "bob": "b"}}`, users: map[string]string{"alice": "a", "bob": "b"}},
+		{name: "no users", file: `{}`, users: map[string]string{}},
+		{name: "null users", file: `{"users": null}`, users: map[string]string{}},
+		{name: "not json", file: `alice=a`, fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profiles.json")
+			t.Setenv("CLI_PROFILES", path)
+
+			if test.file != "" {
+				if err := os.WriteFile(path, []byte(test.file), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			profiles, err := LoadProfiles()
+			if (err != nil) != test.fails {
+				t.Fatalf("LoadProfiles() error = %v, want failure %v", err, test.fails)
+			}
+
+			if err == nil && !reflect.DeepEqual(profiles.Users, test.users) {
+				t.Errorf("LoadProfiles() users = %v, want %v", profiles.Users, test.users)
+			}
+		})
+	}
+}
+
+func TestSaveProfiles(t *testing.T) {
+	// the directory is made if it isnt there yet
+	path := filepath.Join(t.TempDir(), "bin", "profiles.json")
+	t.Setenv("CLI_PROFILES", path)
+
+	profiles, err := LoadProfiles()
+	if err != nil {
+		t.Fatalf("LoadProfiles() error = %v", err)
+	}
+
+	profiles.Users["bob"] = "b"
+	profiles.Users["alice"] = "a"
+
+	if err := profiles.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	saved, err := LoadProfiles()
+	if err != nil {
+		t.Fatalf("LoadProfiles() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(saved.Users, profiles.Users) {
+		t.Errorf("saved users = %v, want %v", saved.Users, profiles.Users)
+	}
+
+	if names := saved.Names(); !reflect.DeepEqual(names, []string{"alice", "bob"}) {
+		t.Errorf("Names() = %v, want them in order", names)
+	}
+
+	if name := saved.NameOf("b"); name != "bob" {
+		t.Errorf("NameOf(b) = %q, want bob", name)
+	}
+
+	if name := saved.NameOf("c"); name != "" {
+		t.Errorf("NameOf(c) = %q, want nothing", name)
+	}
+}
+
+func TestResolveUserId(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		clientId bool
+		id       string
+		usage    bool
+	}{
+		{name: "user", options: Options{UserId: "u", Profile: "alice"}, clientId: true, id: "u"},
+		{name: "profile", options: Options{Profile: "alice"}, clientId: true, id: "a"},
+		{name: "unknown profile", options: Options{Profile: "carol"}, clientId: true, usage: true},
+		{name: "client id", options: Options{}, clientId: true, id: "client"},
+		{name: "nothing", options: Options{}, clientId: false, usage: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+			t.Setenv("CLI_PROFILES", "")
+
+			profiles := &Profiles{Users: map[string]string{"alice": "a"}, path: ProfilesPath()}
+			if err := profiles.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			if test.clientId {
+				if err := os.WriteFile(filepath.Join(dir, "bin", "client.id"), []byte("client\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			id, err := ResolveUserId(&test.options)
+
+			var usage usageError
+			if errors.As(err, &usage) != test.usage {
+				t.Fatalf("ResolveUserId() error = %v, want usage error %v", err, test.usage)
+			}
+
+			if id != test.id {
+				t.Errorf("ResolveUserId() = %q, want %q", id, test.id)
+			}
+		})
+	}
+}
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/grpc/status"
+	"math"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SimulateOptions is how the simulated users behave
+type SimulateOptions struct {
+	Users          int
+	Duration       time.Duration
+	Think          time.Duration
+	LikeChance     float64
+	RequestTimeout time.Duration
+	Seed           uint64
+}
+
+// rpcStats is everything recorded about one RPC over the run. Calls turned away
+// by the rate limiter or because the user is out of likes are counted as limited
+// rather than errors, they are the server doing what it should
+type rpcStats struct {
+	latencies []time.Duration
+	errors    map[string]int
+	limited   map[string]int
+}
+
+// SimulationStats collects the latency and result of every call made by every
+// simulated user, it is shared between them so it is locked
+type SimulationStats struct {
+	mutex sync.Mutex
+	rpcs  map[string]*rpcStats
+}
+
+func NewSimulationStats() *SimulationStats {
+	return &SimulationStats{rpcs: map[string]*rpcStats{}}
+}
+
+func (s *SimulationStats) Record(rpc string, latency time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats, ok := s.rpcs[rpc]
+	if !ok {
+		stats = &rpcStats{errors: map[string]int{}, limited: map[string]int{}}
+		s.rpcs[rpc] = stats
+	}
+
+	stats.latencies = append(stats.latencies, latency)
+
+	if IsQuotaExceeded(err) {
+		stats.limited["QuotaExceeded"] += 1
+		return
+	}
+
+	if _, ok := RateLimitedFor(err); ok {
+		stats.limited["RateLimited"] += 1
+		return
+	}
+
+	if err != nil {
+		stats.errors[status.Code(err).String()] += 1
+	}
+}
+
+func (s *SimulationStats) Calls() (int, int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	calls, errors := 0, 0
+
+	for _, stats := range s.rpcs {
+		calls += len(stats.latencies)
+
+		for _, count := range stats.errors {
+			errors += count
+		}
+	}
+
+	return calls, errors
+}
+
+// RPCReport is the summary of one RPC at the end of a simulation
+type RPCReport struct {
+	RPC        string         `json:"rpc"`
+	Calls      int            `json:"calls"`
+	Errors     int            `json:"errors"`
+	ErrorRate  float64        `json:"error_rate"`
+	Limited    int            `json:"limited"`
+	Throughput float64        `json:"calls_per_second"`
+	P50        time.Duration  `json:"p50_ns"`
+	P90        time.Duration  `json:"p90_ns"`
+	P99        time.Duration  `json:"p99_ns"`
+	Max        time.Duration  `json:"max_ns"`
+	Codes      map[string]int `json:"error_codes"`
+	Limits     map[string]int `json:"limited_by"`
+}
+
+// Report summarises every RPC, with a row named total across all of them at the end
+func (s *SimulationStats) Report(elapsed time.Duration) []RPCReport {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	names := make([]string, 0, len(s.rpcs))
+	for name := range s.rpcs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	total := &rpcStats{errors: map[string]int{}, limited: map[string]int{}}
+	reports := make([]RPCReport, 0, len(names)+1)
+
+	for _, name := range names {
+		stats := s.rpcs[name]
+
+		total.latencies = append(total.latencies, stats.latencies...)
+		for code, count := range stats.errors {
+			total.errors[code] += count
+		}
+
+		for reason, count := range stats.limited {
+			total.limited[reason] += count
+		}
+
+		reports = append(reports, stats.report(name, elapsed))
+	}
+
+	return append(reports, total.report("total", elapsed))
+}
+
+func (s *rpcStats) report(name string, elapsed time.Duration) RPCReport {
+	report := RPCReport{RPC: name, Calls: len(s.latencies), Codes: s.errors, Limits: s.limited}
+
+	for _, count := range s.errors {
+		report.Errors += count
+	}
+
+	for _, count := range s.limited {
+		report.Limited += count
+	}
+
+	if report.Calls == 0 {
+		return report
+	}
+
+	report.ErrorRate = float64(report.Errors) / float64(report.Calls)
+	report.Throughput = float64(report.Calls) / elapsed.Seconds()
+
+	latencies := slices.Clone(s.latencies)
+	slices.Sort(latencies)
+
+	report.P50 = percentile(latencies, 0.50)
+	report.P90 = percentile(latencies, 0.90)
+	report.P99 = percentile(latencies, 0.99)
+	report.Max = latencies[len(latencies)-1]
+
+	return report
+}
+
+// percentile uses the nearest rank so it is always a latency that really happened
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	rank = max(min(rank, len(sorted)-1), 0)
+
+	return sorted[rank]
+}
+
+// simulatedUser acts like someone using the app, each loop it either looks
+// through who liked them, checks their like count or swipes on someone
+type simulatedUser struct {
+	id      string
+	others  []string
+	client  explore.ExploreServiceClient
+	options SimulateOptions
+	stats   *SimulationStats
+	random  *rand.Rand
+}
+
+func (u *simulatedUser) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		switch roll := u.random.Float64(); {
+		case roll < 0.3:
+			u.browse(ctx)
+		case roll < 0.5:
+			u.count(ctx)
+		default:
+			u.swipe(ctx)
+		}
+
+		u.think(ctx)
+	}
+}
+
+// think waits a random amount of time around the think time, exponential so
+// most waits are short with the odd long one like a real person
+func (u *simulatedUser) think(ctx context.Context) {
+	wait := time.Duration(u.random.ExpFloat64() * float64(u.options.Think))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(wait):
+	}
+}
+
+// call times a single request, calls cut off by the simulation ending aren't counted
+func (u *simulatedUser) call(ctx context.Context, rpc string, do func(ctx context.Context) error) error {
+	callCtx := ctx
+	if u.options.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+
+		callCtx, cancel = context.WithTimeout(ctx, u.options.RequestTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := do(callCtx)
+
+	// the request can see the deadline a moment before ctx does
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+
+	u.stats.Record(rpc, time.Since(start), err)
+
+	return err
+}
+
+// browse looks through the likes list, going to the next page about half the time
+func (u *simulatedUser) browse(ctx context.Context) {
+	var paginationToken *string
+
+	for {
+		var response *explore.ListLikedYouResponse
+
+		err := u.call(ctx, "ListLikedYou", func(ctx context.Context) error {
+			var err error
+
+			request := explore.ListLikedYouRequest{RecipientUserId: u.id, PaginationToken: paginationToken}
+			response, err = u.client.ListLikedYou(ctx, &request)
+
+			return err
+		})
+		if err != nil {
+			return
+		}
+
+		paginationToken = response.NextPaginationToken
+		if paginationToken == nil || u.random.Float64() < 0.5 {
+			return
+		}
+
+		u.think(ctx)
+	}
+}
+
+func (u *simulatedUser) count(ctx context.Context) {
+	_ = u.call(ctx, "CountLikedYou", func(ctx context.Context) error {
+		request := explore.CountLikedYouRequest{RecipientUserId: u.id}
+		_, err := u.client.CountLikedYou(ctx, &request)
+
+		return err
+	})
+}
+
+func (u *simulatedUser) swipe(ctx context.Context) {
+	if len(u.others) == 0 {
+		return
+	}
+
+	request := explore.PutDecisionRequest{
+		ActorUserId:     u.id,
+		RecipientUserId: u.others[u.random.IntN(len(u.others))],
+		LikedRecipient:  u.random.Float64() < u.options.LikeChance,
+	}
+
+	_ = u.call(ctx, "PutDecision", func(ctx context.Context) error {
+		_, err := u.client.PutDecision(ctx, &request)
+		return err
+	})
+}
+
+// Simulate runs the simulated users against the server until the duration is up
+// or ctx is cancelled, the users are picked at random from userIds and reused if
+// there are more simulated users than real ones
+func Simulate(ctx context.Context, client explore.ExploreServiceClient, userIds []string, options SimulateOptions) (*SimulationStats, time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, options.Duration)
+	defer cancel()
+
+	stats := NewSimulationStats()
+	random := rand.New(rand.NewPCG(options.Seed, 0))
+
+	ids := slices.Clone(userIds)
+	random.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+
+	var group sync.WaitGroup
+
+	start := time.Now()
+
+	for i := 0; i < options.Users; i++ {
+		id := ids[i%len(ids)]
+
+		others := make([]string, 0, len(ids))
+		for _, other := range ids {
+			if other != id {
+				others = append(others, other)
+			}
+		}
+
+		user := &simulatedUser{
+			id:      id,
+			others:  others,
+			client:  client,
+			options: options,
+			stats:   stats,
+			random:  rand.New(rand.NewPCG(options.Seed, uint64(i+1))),
+		}
+
+		group.Add(1)
+
+		go func() {
+			defer group.Done()
+			user.Run(ctx)
+		}()
+	}
+
+	// progress goes to stderr so it doesn't get mixed in with the report
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				calls, errors := stats.Calls()
+				fmt.Fprintf(os.Stderr, "%v: %v calls, %v errors\n", time.Since(start).Round(time.Second), calls, errors)
+			}
+		}
+	}()
+
+	group.Wait()
+
+	return stats, time.Since(start)
+}
+
+func SimulateCommand(ctx context.Context, runner *Runner, args []string) error {
+	flags := runner.Flags("simulate")
+
+	options := SimulateOptions{}
+	flags.IntVar(&options.Users, "users", 10, "how many users to simulate at once")
+	flags.DurationVar(&options.Duration, "duration", 30*time.Second, "how long to run for")
+	flags.DurationVar(&options.Think, "think", time.Second, "average time a user waits between actions")
+	flags.Float64Var(&options.LikeChance, "like-probability", 0.5, "chance each swipe is a like, between 0 and 1")
+	flags.Uint64Var(&options.Seed, "seed", uint64(time.Now().UnixNano()), "seed for picking users and actions, the same seed picks the same")
+	userIdList := flags.String("user-ids", "", "comma separated user ids to simulate, instead of asking the admin service")
+	userIdFile := flags.String("user-ids-file", "", "file with a user id on each line to simulate, instead of asking the admin service")
+
+	// the timeout for commands would stop the whole simulation, here it is per request
+	_, _, cancel, err := runner.parse(ctx, flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	options.RequestTimeout = runner.Options.Timeout
+
+	if options.Users < 1 {
+		return usagef("--users must be at least 1")
+	}
+
+	if options.LikeChance < 0 || options.LikeChance > 1 {
+		return usagef("--like-probability must be between 0 and 1")
+	}
+
+	userIds, err := simulationUserIds(ctx, runner, *userIdList, *userIdFile)
+	if err != nil {
+		return err
+	}
+
+	if len(userIds) < 2 {
+		return fmt.Errorf("need at least 2 users to simulate, found %v", len(userIds))
+	}
+
+	fmt.Fprintf(os.Stderr, "simulating %v users for %v [seed=%v]\n", options.Users, options.Duration, options.Seed)
+
+	// ctrl+c stops early but still prints what happened so far
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	stats, elapsed := Simulate(ctx, runner.Client(), userIds, options)
+	reports := stats.Report(elapsed)
+
+	table := Table{Headers: []string{"rpc", "calls", "calls/s", "errors", "error rate", "limited", "p50", "p90", "p99", "max", "error codes"}}
+
+	for _, report := range reports {
+		table.Add(
+			report.RPC,
+			report.Calls,
+			fmt.Sprintf("%.1f", report.Throughput),
+			report.Errors,
+			fmt.Sprintf("%.1f%%", report.ErrorRate*100),
+			formatCodes(report.Limited, report.Limits),
+			roundLatency(report.P50),
+			roundLatency(report.P90),
+			roundLatency(report.P99),
+			roundLatency(report.Max),
+			formatCodes(report.Errors, report.Codes),
+		)
+	}
+
+	return runner.Printer.PrintValue(reports, table)
+}
+
+// simulationUserIds is who the simulated users act as, from the flags if they
+// were given otherwise every user the admin service knows about
+func simulationUserIds(ctx context.Context, runner *Runner, list string, file string) ([]string, error) {
+	if list != "" && file != "" {
+		return nil, usagef("only one of --user-ids and --user-ids-file can be used")
+	}
+
+	if list != "" {
+		return strings.Split(list, ","), nil
+	}
+
+	if file != "" {
+		bytes, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var userIds []string
+
+		for _, line := range strings.Split(string(bytes), "\n") {
+			if id := strings.TrimSpace(line); id != "" {
+				userIds = append(userIds, id)
+			}
+		}
+
+		return userIds, nil
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	response, err := runner.Admin().ListUsers(listCtx, &explore.ListUsersRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	userIds := make([]string, len(response.Users))
+	for i, user := range response.Users {
+		userIds[i] = user.UserId
+	}
+
+	return userIds, nil
+}
+
+func roundLatency(latency time.Duration) time.Duration {
+	if latency > time.Second {
+		return latency.Round(time.Millisecond)
+	}
+
+	return latency.Round(10 * time.Microsecond)
+}
+
+func formatCodes(total int, codes map[string]int) string {
+	if total == 0 {
+		return "-"
+	}
+
+	parts := make([]string, 0, len(codes))
+	for code, count := range codes {
+		parts = append(parts, fmt.Sprintf("%v=%v", code, count))
+	}
+
+	sort.Strings(parts)
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	hundred := make([]time.Duration, 100)
+	for i := range hundred {
+		hundred[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{name: "one latency", sorted: []time.Duration{5}, p: 0.5, want: 5},
+		{name: "one latency p99", sorted: []time.Duration{5}, p: 0.99, want: 5},
+		{name: "median of two", sorted: []time.Duration{1, 2}, p: 0.5, want: 1},
+		{name: "median of three", sorted: []time.Duration{1, 2, 3}, p: 0.5, want: 2},
+		{name: "p90 of ten", sorted: []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, p: 0.9, want: 9},
+		{name: "p94 of ten rounds up", sorted: []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, p: 0.94, want: 10},
+		{name: "p91 of ten rounds up", sorted: []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, p: 0.91, want: 10},
+		{name: "p0", sorted: []time.Duration{1, 2, 3}, p: 0, want: 1},
+		{name: "p100", sorted: []time.Duration{1, 2, 3}, p: 1, want: 3},
+		{name: "p50 of a hundred", sorted: hundred, p: 0.5, want: 50 * time.Millisecond},
+		{name: "p99 of a hundred", sorted: hundred, p: 0.99, want: 99 * time.Millisecond},
+		{name: "p99.9 of a hundred", sorted: hundred, p: 0.999, want: 100 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := percentile(test.sorted, test.p); got != test.want {
+				t.Errorf("percentile(%v) = %v, want %v", test.p, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRPCStatsReport(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats rpcStats
+		want  RPCReport
+	}{
+		{
+			name:  "no calls",
+			stats: rpcStats{errors: map[string]int{}, limited: map[string]int{}},
+			want:  RPCReport{RPC: "rpc", Codes: map[string]int{}, Limits: map[string]int{}},
+		},
+		{
+			name: "unsorted latencies",
+			stats: rpcStats{
+				latencies: []time.Duration{4, 1, 3, 2},
+				errors:    map[string]int{},
+				limited:   map[string]int{},
+			},
+			want: RPCReport{
+				RPC: "rpc", Calls: 4, Throughput: 2,
+				P50: 2, P90: 4, P99: 4, Max: 4,
+				Codes: map[string]int{}, Limits: map[string]int{},
+			},
+		},
+		{
+			name: "limited calls aren't errors",
+			stats: rpcStats{
+				latencies: []time.Duration{1, 1, 1, 1},
+				errors:    map[string]int{"Unavailable": 1},
+				limited:   map[string]int{"RateLimited": 2, "QuotaExceeded": 1},
+			},
+			want: RPCReport{
+				RPC: "rpc", Calls: 4, Errors: 1, ErrorRate: 0.25, Limited: 3, Throughput: 2,
+				P50: 1, P90: 1, P99: 1, Max: 1,
+				Codes:  map[string]int{"Unavailable": 1},
+				Limits: map[string]int{"RateLimited": 2, "QuotaExceeded": 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			latencies := slices.Clone(test.stats.latencies)
+
+			report := test.stats.report("rpc", 2*time.Second)
+			if !reflect.DeepEqual(report, test.want) {
+				t.Errorf("report() = %+v, want %+v", report, test.want)
+			}
+
+			if !slices.Equal(test.stats.latencies, latencies) {
+				t.Errorf("report() sorted the recorded latencies in place")
+			}
+		})
+	}
+}
+
+func TestSimulationStatsRecord(t *testing.T) {
+	quota, _ := status.New(codes.ResourceExhausted, "out of likes").WithDetails(&errdetails.QuotaFailure{})
+	rateLimit, _ := status.New(codes.ResourceExhausted, "slow down").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+
+	tests := []struct {
+		name    string
+		err     error
+		errors  map[string]int
+		limited map[string]int
+	}{
+		{name: "ok", err: nil, errors: map[string]int{}, limited: map[string]int{}},
+		{name: "out of likes", err: quota.Err(), errors: map[string]int{}, limited: map[string]int{"QuotaExceeded": 1}},
+		{name: "rate limited", err: rateLimit.Err(), errors: map[string]int{}, limited: map[string]int{"RateLimited": 1}},
+		{name: "exhausted without details", err: status.Error(codes.ResourceExhausted, "full"), errors: map[string]int{"ResourceExhausted": 1}, limited: map[string]int{}},
+		{name: "grpc error", err: status.Error(codes.NotFound, "no user"), errors: map[string]int{"NotFound": 1}, limited: map[string]int{}},
+		{name: "other error", err: errors.New("broken"), errors: map[string]int{"Unknown": 1}, limited: map[string]int{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stats := NewSimulationStats()
+			stats.Record("rpc", time.Millisecond, test.err)
+
+			recorded := stats.rpcs["rpc"]
+			if len(recorded.latencies) != 1 {
+				t.Errorf("latencies = %v, want every call recorded", recorded.latencies)
+			}
+
+			if !reflect.DeepEqual(recorded.errors, test.errors) || !reflect.DeepEqual(recorded.limited, test.limited) {
+				t.Errorf("Record() errors = %v limited = %v, want %v and %v", recorded.errors, recorded.limited, test.errors, test.limited)
+			}
+		})
+	}
+}
+
+func TestSimulationStatsReport(t *testing.T) {
+	quota, _ := status.New(codes.ResourceExhausted, "out of likes").WithDetails(&errdetails.QuotaFailure{})
+
+	stats := NewSimulationStats()
+	stats.Record("PutDecision", 3*time.Millisecond, quota.Err())
+	stats.Record("PutDecision", 1*time.Millisecond, nil)
+	stats.Record("ListLikedYou", 2*time.Millisecond, status.Error(codes.Unavailable, "down"))
+
+	if calls, errors := stats.Calls(); calls != 3 || errors != 1 {
+		t.Errorf("Calls() = %v, %v, want 3 calls and 1 error", calls, errors)
+	}
+
+	reports := stats.Report(time.Second)
+
+	var names []string
+	for _, report := range reports {
+		names = append(names, report.RPC)
+	}
+
+	if !slices.Equal(names, []string{"ListLikedYou", "PutDecision", "total"}) {
+		t.Fatalf("Report() rpcs = %v, want them sorted with the total last", names)
+	}
+
+	total := reports[2]
+	if total.Calls != 3 || total.Errors != 1 || total.Limited != 1 || total.Max != 3*time.Millisecond {
+		t.Errorf("total = %+v, want every rpc added up", total)
+	}
+}
+
+func TestSimulationUserIds(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "users")
+	if err := os.WriteFile(file, []byte("a\n  b \n\nc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		list    string
+		file    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "list", list: "a,b", want: []string{"a", "b"}},
+		{name: "one in the list", list: "a", want: []string{"a"}},
+		{name: "file", file: file, want: []string{"a", "b", "c"}},
+		{name: "missing file", file: filepath.Join(t.TempDir(), "nope"), wantErr: true},
+		{name: "both", list: "a", file: file, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the runner is only needed to ask the admin service when neither is given
+			userIds, err := simulationUserIds(context.Background(), nil, test.list, test.file)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("simulationUserIds() error = %v, want error %v", err, test.wantErr)
+			}
+
+			if !slices.Equal(userIds, test.want) {
+				t.Errorf("simulationUserIds() = %v, want %v", userIds, test.want)
+			}
+		})
+	}
+
+	if _, err := simulationUserIds(context.Background(), nil, "a", file); ExitCode(err) != ExitUsage {
+		t.Errorf("simulationUserIds() with both = %v, want a usage error", err)
+	}
+}
+
+func TestFormatCodes(t *testing.T) {
+	tests := []struct {
+		total int
+		codes map[string]int
+		want  string
+	}{
+		{total: 0, codes: map[string]int{}, want: "-"},
+		{total: 1, codes: map[string]int{"NotFound": 1}, want: "NotFound=1"},
+		{total: 5, codes: map[string]int{"Unavailable": 2, "Internal": 3}, want: "Internal=3,Unavailable=2"},
+	}
+
+	for _, test := range tests {
+		if got := formatCodes(test.total, test.codes); got != test.want {
+			t.Errorf("formatCodes(%v, %v) = %v, want %v", test.total, test.codes, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,674 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"github.com/mattn/go-isatty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// SwipeUndoWindow is how long a swipe waits before it is sent to the server,
+// undo just takes the swipe back before it is ever sent. Swiping again sends the
+// one before straight away so only the last swipe can be undone
+const SwipeUndoWindow = 5 * time.Second
+
+// CanRunTUI is false when stdin or stdout isn't a terminal, like when input is
+// piped in, the plain menu is used then
+func CanRunTUI() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
+
+const (
+	viewSwipe = iota
+	viewLikes
+)
+
+// card is someone to swipe on, people who already liked you come first and then
+// candidates from ListCandidates
+type card struct {
+	UserId   string
+	LikedYou bool
+	Joined   time.Time
+}
+
+type pendingSwipe struct {
+	card  card
+	liked bool
+	id    int
+}
+
+type deckLoadedMsg struct {
+	cards []card
+	token *string
+	err   error
+}
+
+type likersLoadedMsg struct {
+	likers []*explore.ListLikedYouResponse_Liker
+	token  *string
+	total  *uint64
+	err    error
+}
+
+type likeCountMsg struct {
+	count uint64
+	err   error
+}
+
+type decisionSentMsg struct {
+	swipe  pendingSwipe
+	mutual bool
+	err    error
+}
+
+type undoExpiredMsg struct {
+	id int
+}
+
+type likeEventMsg struct {
+	event *explore.LikeEvent
+	err   error
+}
+
+type matchEventMsg struct {
+	event *explore.MatchEvent
+	err   error
+}
+
+type TUI struct {
+	ctx     context.Context
+	client  explore.ExploreServiceClient
+	likes   explore.ExploreService_WatchLikesClient
+	matches explore.ExploreService_WatchMatchesClient
+
+	width  int
+	height int
+	view   int
+
+	deck        []card
+	seen        map[string]bool
+	deckToken   *string
+	deckStarted bool
+	deckDone    bool
+	deckLoading bool
+	pending     *pendingSwipe
+	swipes      int
+
+	// unsent is every swipe being sent that hasn't come back yet, anything left
+	// when quitting is sent again before exiting. Sending the same decision twice
+	// doesn't change anything so it doesn't matter if it did make it
+	unsent map[int]pendingSwipe
+
+	likeCount     uint64
+	likers        []*explore.ListLikedYouResponse_Liker
+	likersToken   *string
+	likersTotal   *uint64
+	likersDone    bool
+	likersLoading bool
+	cursor        int
+	offset        int
+
+	// celebration is who was just matched with, shown over everything until a key is pressed
+	celebration string
+	celebrated  map[string]bool
+
+	status string
+}
+
+// RunTUI runs the full screen swipe view until the user quits
+func RunTUI(ctx context.Context, client explore.ExploreServiceClient) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	request := explore.WatchRequest{UserId: GLobalClientID}
+
+	likes, err := client.WatchLikes(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	matches, err := client.WatchMatches(ctx, &request)
+	if err != nil {
+		return err
+	}
+
+	model := &TUI{
+		ctx:        ctx,
+		client:     client,
+		likes:      likes,
+		matches:    matches,
+		unsent:     map[int]pendingSwipe{},
+		seen:       map[string]bool{},
+		celebrated: map[string]bool{},
+	}
+
+	// anything logged would draw over the screen
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	final, err := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	if err != nil {
+		return err
+	}
+
+	// a swipe still waiting on its undo window is sent on the way out
+	if pending := final.(*TUI).pending; pending != nil {
+		model.unsent[pending.id] = *pending
+	}
+
+	for _, swipe := range model.unsent {
+		msg := model.send(swipe)().(decisionSentMsg)
+		if msg.err != nil {
+			return msg.err
+		}
+
+		if msg.mutual && swipe.liked && !model.celebrated[swipe.card.UserId] {
+			fmt.Printf("It's a match with %v congrats!!\n", swipe.card.UserId)
+		}
+	}
+
+	return nil
+}
+
+func (m *TUI) Init() tea.Cmd {
+	return tea.Batch(m.loadDeck(), m.loadLikers(), m.loadLikeCount(), m.waitForLike(), m.waitForMatch())
+}
+
+func (m *TUI) loadDeck() tea.Cmd {
+	if m.deckLoading || m.deckDone {
+		return nil
+	}
+
+	m.deckLoading = true
+	first := !m.deckStarted
+	m.deckStarted = true
+	token := m.deckToken
+
+	return func() tea.Msg {
+		var cards []card
+
+		if first {
+			request := explore.ListLikedYouRequest{RecipientUserId: GLobalClientID}
+
+			response, err := m.client.ListNewLikedYou(m.ctx, &request)
+			if err != nil {
+				return deckLoadedMsg{err: err}
+			}
+
+			for _, liker := range response.Likers {
+				if !liker.Redacted {
+					cards = append(cards, card{UserId: liker.ActorId, LikedYou: true})
+				}
+			}
+		}
+
+		request := explore.ListCandidatesRequest{ActorUserId: GLobalClientID, PaginationToken: token}
+
+		response, err := m.client.ListCandidates(m.ctx, &request)
+		if err != nil {
+			return deckLoadedMsg{err: err}
+		}
+
+		for _, candidate := range response.Candidates {
+			cards = append(cards, card{UserId: candidate.UserId, Joined: UnixTime(candidate.UnixTimestamp)})
+		}
+
+		return deckLoadedMsg{cards: cards, token: response.NextPaginationToken}
+	}
+}
+
+func (m *TUI) loadLikers() tea.Cmd {
+	if m.likersLoading || m.likersDone {
+		return nil
+	}
+
+	m.likersLoading = true
+	token := m.likersToken
+
+	return func() tea.Msg {
+		request := explore.ListLikedYouRequest{RecipientUserId: GLobalClientID, PaginationToken: token}
+
+		response, err := m.client.ListLikedYou(m.ctx, &request)
+		if err != nil {
+			return likersLoadedMsg{err: err}
+		}
+
+		return likersLoadedMsg{likers: response.Likers, token: response.NextPaginationToken, total: response.TotalCount}
+	}
+}
+
+func (m *TUI) loadLikeCount() tea.Cmd {
+	return func() tea.Msg {
+		request := explore.CountLikedYouRequest{RecipientUserId: GLobalClientID}
+
+		response, err := m.client.CountLikedYou(m.ctx, &request)
+		if err != nil {
+			return likeCountMsg{err: err}
+		}
+
+		return likeCountMsg{count: response.Count}
+	}
+}
+
+func (m *TUI) waitForLike() tea.Cmd {
+	return func() tea.Msg {
+		event, err := m.likes.Recv()
+		return likeEventMsg{event: event, err: err}
+	}
+}
+
+func (m *TUI) waitForMatch() tea.Cmd {
+	return func() tea.Msg {
+		event, err := m.matches.Recv()
+		return matchEventMsg{event: event, err: err}
+	}
+}
+
+func (m *TUI) send(swipe pendingSwipe) tea.Cmd {
+	m.unsent[swipe.id] = swipe
+
+	return func() tea.Msg {
+		request := explore.PutDecisionRequest{
+			ActorUserId:     GLobalClientID,
+			RecipientUserId: swipe.card.UserId,
+			LikedRecipient:  swipe.liked,
+		}
+
+		response, err := m.client.PutDecision(m.ctx, &request)
+		if err != nil {
+			return decisionSentMsg{swipe: swipe, err: err}
+		}
+
+		return decisionSentMsg{swipe: swipe, mutual: response.MutualLikes}
+	}
+}
+
+// addCards puts cards on the deck skipping anyone already seen, at is where in
+// the deck they go
+func (m *TUI) addCards(at int, cards ...card) {
+	var fresh []card
+
+	for _, card := range cards {
+		if m.seen[card.UserId] || card.UserId == GLobalClientID {
+			continue
+		}
+
+		m.seen[card.UserId] = true
+		fresh = append(fresh, card)
+	}
+
+	at = min(at, len(m.deck))
+	m.deck = append(m.deck[:at], append(fresh, m.deck[at:]...)...)
+}
+
+func (m *TUI) swipe(liked bool) tea.Cmd {
+	if len(m.deck) == 0 {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+
+	if m.pending != nil {
+		cmds = append(cmds, m.send(*m.pending))
+	}
+
+	m.swipes += 1
+	m.pending = &pendingSwipe{card: m.deck[0], liked: liked, id: m.swipes}
+	m.deck = m.deck[1:]
+	m.status = ""
+
+	id := m.swipes
+	cmds = append(cmds, tea.Tick(SwipeUndoWindow, func(time.Time) tea.Msg { return undoExpiredMsg{id: id} }))
+
+	if len(m.deck) < 3 {
+		cmds = append(cmds, m.loadDeck())
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (m *TUI) undo() {
+	if m.pending == nil {
+		m.status = fmt.Sprintf("nothing to undo, a swipe can only be undone for %v", SwipeUndoWindow)
+		return
+	}
+
+	m.deck = append([]card{m.pending.card}, m.deck...)
+	m.pending = nil
+	m.status = "undone"
+}
+
+func (m *TUI) celebrate(userId string) {
+	if m.celebrated[userId] {
+		return
+	}
+
+	m.celebrated[userId] = true
+	m.celebration = userId
+}
+
+func (m *TUI) listHeight() int {
+	if m.height == 0 {
+		return 20
+	}
+
+	return max(m.height-8, 3)
+}
+
+func (m *TUI) moveCursor(by int) tea.Cmd {
+	m.cursor = max(min(m.cursor+by, len(m.likers)-1), 0)
+
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+
+	if m.cursor >= m.offset+m.listHeight() {
+		m.offset = m.cursor - m.listHeight() + 1
+	}
+
+	// the next page is loaded once the cursor gets close to the bottom
+	if m.cursor >= len(m.likers)-3 {
+		return m.loadLikers()
+	}
+
+	return nil
+}
+
+func (m *TUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	case deckLoadedMsg:
+		m.deckLoading = false
+
+		if msg.err != nil {
+			m.status = fmt.Sprintf("could not load people to swipe on: %v", status.Convert(msg.err).Message())
+			m.deckDone = true
+			return m, nil
+		}
+
+		m.addCards(len(m.deck), msg.cards...)
+		m.deckToken = msg.token
+		m.deckDone = msg.token == nil
+
+		// everyone on the page could have been seen already so keep going
+		if len(m.deck) < 3 {
+			return m, m.loadDeck()
+		}
+
+		return m, nil
+	case likersLoadedMsg:
+		m.likersLoading = false
+
+		if msg.err != nil {
+			m.status = fmt.Sprintf("could not load your likes: %v", status.Convert(msg.err).Message())
+			m.likersDone = true
+			return m, nil
+		}
+
+		m.likers = append(m.likers, msg.likers...)
+		m.likersToken = msg.token
+		m.likersTotal = msg.total
+		m.likersDone = msg.token == nil
+
+		return m, nil
+	case likeCountMsg:
+		if msg.err == nil {
+			m.likeCount = msg.count
+		}
+
+		return m, nil
+	case undoExpiredMsg:
+		if m.pending == nil || m.pending.id != msg.id {
+			return m, nil
+		}
+
+		swipe := *m.pending
+		m.pending = nil
+
+		return m, m.send(swipe)
+	case decisionSentMsg:
+		delete(m.unsent, msg.swipe.id)
+
+		if IsQuotaExceeded(msg.err) {
+			// put them back so they can be liked tomorrow or passed on now
+			m.deck = append([]card{msg.swipe.card}, m.deck...)
+			m.status = "You are out of likes for today, come back tomorrow!"
+			return m, nil
+		}
+
+		if wait, ok := RateLimitedFor(msg.err); ok {
+			m.deck = append([]card{msg.swipe.card}, m.deck...)
+			m.status = fmt.Sprintf("Slow down! try again in %v", max(wait.Round(time.Second), time.Second))
+			return m, nil
+		}
+
+		if msg.err != nil {
+			m.status = fmt.Sprintf("could not save your decision on %v: %v", msg.swipe.card.UserId, status.Convert(msg.err).Message())
+			return m, nil
+		}
+
+		if msg.mutual && msg.swipe.liked {
+			m.celebrate(msg.swipe.card.UserId)
+		}
+
+		return m, nil
+	case likeEventMsg:
+		if msg.err != nil {
+			if status.Code(msg.err) != codes.Canceled {
+				m.status = "stopped watching for new likes"
+			}
+
+			return m, nil
+		}
+
+		m.likeCount += 1
+		m.likers = append([]*explore.ListLikedYouResponse_Liker{msg.event.Liker}, m.likers...)
+
+		if m.cursor > 0 {
+			m.cursor += 1
+			m.offset += 1
+		}
+
+		if !msg.event.Liker.Redacted {
+			m.status = fmt.Sprintf("%v just liked you!", msg.event.Liker.ActorId)
+			m.addCards(1, card{UserId: msg.event.Liker.ActorId, LikedYou: true})
+		} else {
+			m.status = "someone new liked you!"
+		}
+
+		return m, m.waitForLike()
+	case matchEventMsg:
+		if msg.err != nil {
+			return m, nil
+		}
+
+		m.celebrate(msg.event.UserId)
+
+		return m, m.waitForMatch()
+	}
+
+	return m, nil
+}
+
+func (m *TUI) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	if m.celebration != "" {
+		m.celebration = ""
+		return m, nil
+	}
+
+	switch key {
+	case "q", "esc":
+		return m, tea.Quit
+	case "tab":
+		m.view = (m.view + 1) % 2
+		return m, nil
+	}
+
+	if m.view == viewSwipe {
+		switch key {
+		case "right", "l", "y":
+			return m, m.swipe(true)
+		case "left", "h", "n":
+			return m, m.swipe(false)
+		case "u":
+			m.undo()
+		}
+
+		return m, nil
+	}
+
+	switch key {
+	case "down", "j":
+		return m, m.moveCursor(1)
+	case "up", "k":
+		return m, m.moveCursor(-1)
+	case "pgdown", " ":
+		return m, m.moveCursor(m.listHeight())
+	case "pgup":
+		return m, m.moveCursor(-m.listHeight())
+	}
+
+	return m, nil
+}
+
+var (
+	titleStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	tabStyle       = lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("245"))
+	activeTabStyle = tabStyle.Foreground(lipgloss.Color("205")).Bold(true).Underline(true)
+	cardStyle      = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("205")).Padding(1, 4).Width(48).Align(lipgloss.Center)
+	celebrateStyle = lipgloss.NewStyle().Border(lipgloss.DoubleBorder()).BorderForeground(lipgloss.Color("212")).Padding(2, 6).Align(lipgloss.Center).Bold(true)
+	dimStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	selectedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+)
+
+func (m *TUI) View() string {
+	swipeTab, likesTab := activeTabStyle, tabStyle
+	if m.view == viewLikes {
+		swipeTab, likesTab = tabStyle, activeTabStyle
+	}
+
+	header := lipgloss.JoinHorizontal(lipgloss.Top,
+		titleStyle.Render("protoexplore"),
+		"  ",
+		titleStyle.Render(fmt.Sprintf("♥ %v", m.likeCount)),
+		"  ",
+		swipeTab.Render("swipe"),
+		likesTab.Render("likes"),
+		"  ",
+		dimStyle.Render(GLobalClientID),
+	)
+
+	var body string
+
+	switch {
+	case m.celebration != "":
+		body = celebrateStyle.Render(fmt.Sprintf("It's a match!\n\nyou and %v liked each other\n\n%v", m.celebration, dimStyle.Render("press any key")))
+	case m.view == viewSwipe:
+		body = m.swipeView()
+	default:
+		body = m.likesView()
+	}
+
+	help := "←/h pass  →/l like  u undo  tab likes  q quit"
+	if m.view == viewLikes {
+		help = "↑/k ↓/j scroll  pgup/pgdown page  tab swipe  q quit"
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", body, "", m.status, dimStyle.Render(help))
+}
+
+func (m *TUI) swipeView() string {
+	var pending string
+
+	if m.pending != nil {
+		decision := "passed on"
+		if m.pending.liked {
+			decision = "liked"
+		}
+
+		pending = dimStyle.Render(fmt.Sprintf("you %v %v, u to undo", decision, m.pending.card.UserId))
+	}
+
+	if len(m.deck) == 0 {
+		if m.deckLoading {
+			return lipgloss.JoinVertical(lipgloss.Left, cardStyle.Render("finding people..."), pending)
+		}
+
+		return lipgloss.JoinVertical(lipgloss.Left, cardStyle.Render("You have seen everyone for now, check back later"), pending)
+	}
+
+	current := m.deck[0]
+
+	detail := dimStyle.Render(fmt.Sprintf("joined %v", RelativeTime(current.Joined, time.Now())))
+	if current.LikedYou {
+		detail = selectedStyle.Render("already liked you!")
+	}
+
+	card := cardStyle.Render(fmt.Sprintf("%v\n\n%v", current.UserId, detail))
+
+	return lipgloss.JoinVertical(lipgloss.Left, card, dimStyle.Render(fmt.Sprintf("%v more to go", len(m.deck)-1)), pending)
+}
+
+func (m *TUI) likesView() string {
+	if len(m.likers) == 0 {
+		if m.likersLoading {
+			return "loading your likes..."
+		}
+
+		return "No one has liked you yet"
+	}
+
+	var lines []string
+
+	end := min(m.offset+m.listHeight(), len(m.likers))
+
+	for i := m.offset; i < end; i++ {
+		liker := m.likers[i]
+
+		who := liker.ActorId
+		if liker.Redacted {
+			who = "someone"
+		}
+
+		line := fmt.Sprintf("%-38v %v", who, RelativeTime(UnixTime(liker.UnixTimestamp), time.Now()))
+
+		if i == m.cursor {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+
+		lines = append(lines, line)
+	}
+
+	switch {
+	case m.likersLoading:
+		lines = append(lines, dimStyle.Render("  loading more..."))
+	case m.likersTotal != nil:
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  %v people liked you, go premium to see who they are", *m.likersTotal)))
+	case m.likersDone:
+		lines = append(lines, dimStyle.Render("  that was all of your likes"))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestTUI is a TUI with the deck already loaded so nothing more is asked for
+func newTestTUI(client explore.ExploreServiceClient, ids ...string) *TUI {
+	m := &TUI{
+		ctx:        context.Background(),
+		client:     client,
+		unsent:     map[int]pendingSwipe{},
+		seen:       map[string]bool{},
+		celebrated: map[string]bool{},
+		deckDone:   true,
+	}
+
+	for _, id := range ids {
+		m.addCards(len(m.deck), card{UserId: id})
+	}
+
+	return m
+}
+
+func keyPress(key string) tea.KeyMsg {
+	if key == "right" {
+		return tea.KeyMsg{Type: tea.KeyRight}
+	}
+
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+func deckIds(m *TUI) []string {
+	ids := []string{}
+	for _, card := range m.deck {
+		ids = append(ids, card.UserId)
+	}
+
+	return ids
+}
+
+func TestTUISwipe(t *testing.T) {
+	client := &fakeExploreClient{}
+	m := newTestTUI(client, "a", "b", "c", "d", "e")
+
+	// a swipe waits for its undo window before anything is sent
+	m.Update(keyPress("right"))
+
+	if m.pending == nil || m.pending.card.UserId != "a" || !m.pending.liked || len(m.unsent) != 0 {
+		t.Fatalf("after liking pending = %+v and unsent %v, want a like of a waiting", m.pending, m.unsent)
+	}
+
+	// swiping again sends the one before straight away
+	m.Update(keyPress("h"))
+
+	if m.pending == nil || m.pending.card.UserId != "b" || m.pending.liked {
+		t.Fatalf("after passing pending = %+v, want a pass on b waiting", m.pending)
+	}
+
+	if sent, ok := m.unsent[1]; !ok || sent.card.UserId != "a" || len(m.unsent) != 1 {
+		t.Fatalf("unsent = %v, want the like of a", m.unsent)
+	}
+
+	// only the last swipe can be undone
+	m.Update(keyPress("u"))
+	m.Update(keyPress("u"))
+
+	if m.pending != nil || !reflect.DeepEqual(deckIds(m), []string{"b", "c", "d", "e"}) {
+		t.Fatalf("after undoing pending = %+v and deck %v, want b back on top", m.pending, deckIds(m))
+	}
+
+	if m.status == "undone" {
+		t.Errorf("undoing twice status = %q, want nothing to undo", m.status)
+	}
+
+	m.Update(keyPress("n"))
+
+	// the window running out for an older swipe does nothing
+	if _, cmd := m.Update(undoExpiredMsg{id: 1}); cmd != nil || m.pending == nil {
+		t.Errorf("an old undo window sent the pending swipe")
+	}
+
+	_, cmd := m.Update(undoExpiredMsg{id: m.swipes})
+	if m.pending != nil || cmd == nil {
+		t.Fatalf("the undo window running out didnt send the swipe")
+	}
+
+	msg := cmd().(decisionSentMsg)
+	if msg.swipe.card.UserId != "b" || msg.swipe.liked || len(client.decisions) != 1 || client.decisions[0].RecipientUserId != "b" {
+		t.Errorf("sent %+v, want a pass on b", client.decisions)
+	}
+
+	m.Update(msg)
+
+	if _, ok := m.unsent[msg.swipe.id]; ok {
+		t.Errorf("swipe is still unsent once it came back")
+	}
+}
+
+func TestTUIDecisionSent(t *testing.T) {
+	quota := withDetails(codes.ResourceExhausted, &errdetails.QuotaFailure{})
+	rateLimited := withDetails(codes.ResourceExhausted, &errdetails.RetryInfo{RetryDelay: durationpb.New(300 * time.Millisecond)})
+
+	tests := []struct {
+		name   string
+		liked  bool
+		mutual bool
+		err    error
+		// deck is who is on the deck after, the swipe is put back when it can be tried again
+		deck        []string
+		status      string
+		celebration string
+	}{
+		{name: "like", liked: true, deck: []string{"b"}},
+		{name: "match", liked: true, mutual: true, deck: []string{"b"}, celebration: "a"},
+		{name: "pass on someone who liked you", liked: false, mutual: true, deck: []string{"b"}},
+		{name: "out of likes", liked: true, err: quota, deck: []string{"a", "b"}, status: "You are out of likes for today, come back tomorrow!"},
+		{name: "rate limited", liked: true, err: rateLimited, deck: []string{"a", "b"}, status: "Slow down! try again in 1s"},
+		{name: "failed", liked: true, err: status.Error(codes.Internal, "oops"), deck: []string{"b"}, status: "could not save your decision on a: oops"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newTestTUI(&fakeExploreClient{}, "a", "b")
+
+			m.swipe(test.liked)
+			swipe := *m.pending
+			m.pending = nil
+			m.unsent[swipe.id] = swipe
+
+			m.Update(decisionSentMsg{swipe: swipe, mutual: test.mutual, err: test.err})
+
+			if !reflect.DeepEqual(deckIds(m), test.deck) {
+				t.Errorf("deck = %v, want %v", deckIds(m), test.deck)
+			}
+
+			if m.status != test.status {
+				t.Errorf("status = %q, want %q", m.status, test.status)
+			}
+
+			if m.celebration != test.celebration {
+				t.Errorf("celebrating %q, want %q", m.celebration, test.celebration)
+			}
+
+			if len(m.unsent) != 0 {
+				t.Errorf("unsent = %v, want nothing once it came back", m.unsent)
+			}
+		})
+	}
+}
+
+func TestTUIEvents(t *testing.T) {
+	GLobalClientID = "me"
+	t.Cleanup(func() { GLobalClientID = "" })
+
+	m := newTestTUI(&fakeExploreClient{}, "a", "b", "c")
+
+	// someone new who liked you goes second so the card being looked at doesnt change
+	m.Update(likeEventMsg{event: &explore.LikeEvent{Liker: &explore.ListLikedYouResponse_Liker{ActorId: "x"}}})
+	// anyone already on the deck isnt added again
+	m.Update(likeEventMsg{event: &explore.LikeEvent{Liker: &explore.ListLikedYouResponse_Liker{ActorId: "c"}}})
+	// a redacted liker is counted but there is nobody to swipe on
+	m.Update(likeEventMsg{event: &explore.LikeEvent{Liker: &explore.ListLikedYouResponse_Liker{Redacted: true}}})
+
+	if !reflect.DeepEqual(deckIds(m), []string{"a", "x", "b", "c"}) {
+		t.Errorf("deck = %v, want x second", deckIds(m))
+	}
+
+	if m.likeCount != 3 || len(m.likers) != 3 || m.status != "someone new liked you!" {
+		t.Errorf("like count = %v with %v likers and status %q, want 3 and someone new", m.likeCount, len(m.likers), m.status)
+	}
+
+	// a page of candidates skips anyone seen and yourself
+	m.deckDone = false
+	m.Update(deckLoadedMsg{cards: []card{{UserId: "b"}, {UserId: "me"}, {UserId: "y"}}})
+
+	if !reflect.DeepEqual(deckIds(m), []string{"a", "x", "b", "c", "y"}) || !m.deckDone {
+		t.Errorf("deck = %v and done %v, want y added to the end and no more pages", deckIds(m), m.deckDone)
+	}
+
+	// the match is celebrated once, however it is heard about
+	m.Update(matchEventMsg{event: &explore.MatchEvent{UserId: "x"}})
+
+	if m.celebration != "x" {
+		t.Fatalf("celebrating %q, want x", m.celebration)
+	}
+
+	// any key gets rid of the celebration without swiping
+	m.Update(keyPress("y"))
+
+	if m.celebration != "" || m.pending != nil {
+		t.Errorf("after a key celebration = %q and pending %+v, want it dismissed and nothing swiped", m.celebration, m.pending)
+	}
+
+	m.Update(decisionSentMsg{swipe: pendingSwipe{card: card{UserId: "x"}, liked: true}, mutual: true})
+
+	if m.celebration != "" {
+		t.Errorf("celebrated x twice")
+	}
+}
@@ -77,6 +77,7 @@ type ListLikedYouResponse struct {
 	state               protoimpl.MessageState        `protogen:"open.v1"`
 	Likers              []*ListLikedYouResponse_Liker `protobuf:"bytes,1,rep,name=likers,proto3" json:"likers,omitempty"`
 	NextPaginationToken *string                       `protobuf:"bytes,2,opt,name=next_pagination_token,json=nextPaginationToken,proto3,oneof" json:"next_pagination_token,omitempty"`
+	TotalCount          *uint64                       `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3,oneof" json:"total_count,omitempty"` // Set when likers are redacted so the recipient still knows how many likes they have
 	unknownFields       protoimpl.UnknownFields
 	sizeCache           protoimpl.SizeCache
 }
@@ -125,16 +126,113 @@ func (x *ListLikedYouResponse) GetNextPaginationToken() string {
 	return ""
 }
 
+func (x *ListLikedYouResponse) GetTotalCount() uint64 {
+	if x != nil && x.TotalCount != nil {
+		return *x.TotalCount
+	}
+	return 0
+}
+
+type ListMatchesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMatchesRequest) Reset() {
+	*x = ListMatchesRequest{}
+	mi := &file_explore_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchesRequest) ProtoMessage() {}
+
+func (x *ListMatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchesRequest.ProtoReflect.Descriptor instead.
+func (*ListMatchesRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListMatchesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListMatchesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []*MatchEvent          `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMatchesResponse) Reset() {
+	*x = ListMatchesResponse{}
+	mi := &file_explore_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMatchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchesResponse) ProtoMessage() {}
+
+func (x *ListMatchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchesResponse.ProtoReflect.Descriptor instead.
+func (*ListMatchesResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListMatchesResponse) GetMatches() []*MatchEvent {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
 type CountLikedYouRequest struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	RecipientUserId string                 `protobuf:"bytes,1,opt,name=recipient_user_id,json=recipientUserId,proto3" json:"recipient_user_id,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	RecipientUserId    string                 `protobuf:"bytes,1,opt,name=recipient_user_id,json=recipientUserId,proto3" json:"recipient_user_id,omitempty"`
+	IncludeBreakdown   bool                   `protobuf:"varint,2,opt,name=include_breakdown,json=includeBreakdown,proto3" json:"include_breakdown,omitempty"`               // Also split the count up by what the recipient did about each like
+	SinceUnixTimestamp *uint64                `protobuf:"varint,3,opt,name=since_unix_timestamp,json=sinceUnixTimestamp,proto3,oneof" json:"since_unix_timestamp,omitempty"` // Also count the likes made after this time, implies include_breakdown
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *CountLikedYouRequest) Reset() {
 	*x = CountLikedYouRequest{}
-	mi := &file_explore_service_proto_msgTypes[2]
+	mi := &file_explore_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -146,7 +244,7 @@ func (x *CountLikedYouRequest) String() string {
 func (*CountLikedYouRequest) ProtoMessage() {}
 
 func (x *CountLikedYouRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_explore_service_proto_msgTypes[2]
+	mi := &file_explore_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -159,7 +257,7 @@ func (x *CountLikedYouRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CountLikedYouRequest.ProtoReflect.Descriptor instead.
 func (*CountLikedYouRequest) Descriptor() ([]byte, []int) {
-	return file_explore_service_proto_rawDescGZIP(), []int{2}
+	return file_explore_service_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CountLikedYouRequest) GetRecipientUserId() string {
@@ -169,16 +267,31 @@ func (x *CountLikedYouRequest) GetRecipientUserId() string {
 	return ""
 }
 
+func (x *CountLikedYouRequest) GetIncludeBreakdown() bool {
+	if x != nil {
+		return x.IncludeBreakdown
+	}
+	return false
+}
+
+func (x *CountLikedYouRequest) GetSinceUnixTimestamp() uint64 {
+	if x != nil && x.SinceUnixTimestamp != nil {
+		return *x.SinceUnixTimestamp
+	}
+	return 0
+}
+
 type CountLikedYouResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Count         uint64                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	state         protoimpl.MessageState           `protogen:"open.v1"`
+	Count         uint64                           `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Breakdown     *CountLikedYouResponse_Breakdown `protobuf:"bytes,2,opt,name=breakdown,proto3,oneof" json:"breakdown,omitempty"` // Only set if include_breakdown or since_unix_timestamp was given
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CountLikedYouResponse) Reset() {
 	*x = CountLikedYouResponse{}
-	mi := &file_explore_service_proto_msgTypes[3]
+	mi := &file_explore_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -190,7 +303,7 @@ func (x *CountLikedYouResponse) String() string {
 func (*CountLikedYouResponse) ProtoMessage() {}
 
 func (x *CountLikedYouResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_explore_service_proto_msgTypes[3]
+	mi := &file_explore_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -203,7 +316,7 @@ func (x *CountLikedYouResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CountLikedYouResponse.ProtoReflect.Descriptor instead.
 func (*CountLikedYouResponse) Descriptor() ([]byte, []int) {
-	return file_explore_service_proto_rawDescGZIP(), []int{3}
+	return file_explore_service_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CountLikedYouResponse) GetCount() uint64 {
@@ -213,18 +326,26 @@ func (x *CountLikedYouResponse) GetCount() uint64 {
 	return 0
 }
 
+func (x *CountLikedYouResponse) GetBreakdown() *CountLikedYouResponse_Breakdown {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
 type PutDecisionRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	ActorUserId     string                 `protobuf:"bytes,1,opt,name=actor_user_id,json=actorUserId,proto3" json:"actor_user_id,omitempty"`
 	RecipientUserId string                 `protobuf:"bytes,2,opt,name=recipient_user_id,json=recipientUserId,proto3" json:"recipient_user_id,omitempty"`
 	LikedRecipient  bool                   `protobuf:"varint,3,opt,name=liked_recipient,json=likedRecipient,proto3" json:"liked_recipient,omitempty"`
+	IdempotencyKey  *string                `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3,oneof" json:"idempotency_key,omitempty"` // Retrying with the same key within 24 hours returns the original response without changing anything
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *PutDecisionRequest) Reset() {
 	*x = PutDecisionRequest{}
-	mi := &file_explore_service_proto_msgTypes[4]
+	mi := &file_explore_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -236,7 +357,7 @@ func (x *PutDecisionRequest) String() string {
 func (*PutDecisionRequest) ProtoMessage() {}
 
 func (x *PutDecisionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_explore_service_proto_msgTypes[4]
+	mi := &file_explore_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -249,7 +370,7 @@ func (x *PutDecisionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PutDecisionRequest.ProtoReflect.Descriptor instead.
 func (*PutDecisionRequest) Descriptor() ([]byte, []int) {
-	return file_explore_service_proto_rawDescGZIP(), []int{4}
+	return file_explore_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PutDecisionRequest) GetActorUserId() string {
@@ -273,16 +394,24 @@ func (x *PutDecisionRequest) GetLikedRecipient() bool {
 	return false
 }
 
+func (x *PutDecisionRequest) GetIdempotencyKey() string {
+	if x != nil && x.IdempotencyKey != nil {
+		return *x.IdempotencyKey
+	}
+	return ""
+}
+
 type PutDecisionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MutualLikes   bool                   `protobuf:"varint,1,opt,name=mutual_likes,json=mutualLikes,proto3" json:"mutual_likes,omitempty"` // True if both users like each other
+	Replayed      bool                   `protobuf:"varint,2,opt,name=replayed,proto3" json:"replayed,omitempty"`                          // True if the idempotency key was already used and this is the original response
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PutDecisionResponse) Reset() {
 	*x = PutDecisionResponse{}
-	mi := &file_explore_service_proto_msgTypes[5]
+	mi := &file_explore_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -294,7 +423,7 @@ func (x *PutDecisionResponse) String() string {
 func (*PutDecisionResponse) ProtoMessage() {}
 
 func (x *PutDecisionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_explore_service_proto_msgTypes[5]
+	mi := &file_explore_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -307,7 +436,7 @@ func (x *PutDecisionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PutDecisionResponse.ProtoReflect.Descriptor instead.
 func (*PutDecisionResponse) Descriptor() ([]byte, []int) {
-	return file_explore_service_proto_rawDescGZIP(), []int{5}
+	return file_explore_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PutDecisionResponse) GetMutualLikes() bool {
@@ -317,29 +446,36 @@ func (x *PutDecisionResponse) GetMutualLikes() bool {
 	return false
 }
 
-type ListLikedYouResponse_Liker struct {
+func (x *PutDecisionResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+type PutDecisionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ActorId       string                 `protobuf:"bytes,1,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
-	UnixTimestamp uint64                 `protobuf:"varint,2,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
+	Decisions     []*PutDecisionRequest  `protobuf:"bytes,1,rep,name=decisions,proto3" json:"decisions,omitempty"` // Applied in order, at most 100 and all from the same actor
+	Atomic        bool                   `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`      // If true the first failure rolls back every decision and is returned as the error
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListLikedYouResponse_Liker) Reset() {
-	*x = ListLikedYouResponse_Liker{}
-	mi := &file_explore_service_proto_msgTypes[6]
+func (x *PutDecisionsRequest) Reset() {
+	*x = PutDecisionsRequest{}
+	mi := &file_explore_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListLikedYouResponse_Liker) String() string {
+func (x *PutDecisionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListLikedYouResponse_Liker) ProtoMessage() {}
+func (*PutDecisionsRequest) ProtoMessage() {}
 
-func (x *ListLikedYouResponse_Liker) ProtoReflect() protoreflect.Message {
-	mi := &file_explore_service_proto_msgTypes[6]
+func (x *PutDecisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -350,56 +486,2432 @@ func (x *ListLikedYouResponse_Liker) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListLikedYouResponse_Liker.ProtoReflect.Descriptor instead.
-func (*ListLikedYouResponse_Liker) Descriptor() ([]byte, []int) {
-	return file_explore_service_proto_rawDescGZIP(), []int{1, 0}
+// Deprecated: Use PutDecisionsRequest.ProtoReflect.Descriptor instead.
+func (*PutDecisionsRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ListLikedYouResponse_Liker) GetActorId() string {
+func (x *PutDecisionsRequest) GetDecisions() []*PutDecisionRequest {
 	if x != nil {
-		return x.ActorId
+		return x.Decisions
+	}
+	return nil
+}
+
+func (x *PutDecisionsRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+type PutDecisionsResponse struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	Results       []*PutDecisionsResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // One for each decision in the same order as the request
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutDecisionsResponse) Reset() {
+	*x = PutDecisionsResponse{}
+	mi := &file_explore_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutDecisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutDecisionsResponse) ProtoMessage() {}
+
+func (x *PutDecisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutDecisionsResponse.ProtoReflect.Descriptor instead.
+func (*PutDecisionsResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PutDecisionsResponse) GetResults() []*PutDecisionsResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuotaRequest) Reset() {
+	*x = GetQuotaRequest{}
+	mi := &file_explore_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuotaRequest) ProtoMessage() {}
+
+func (x *GetQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuotaRequest.ProtoReflect.Descriptor instead.
+func (*GetQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetQuotaRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *ListLikedYouResponse_Liker) GetUnixTimestamp() uint64 {
+type GetQuotaResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Tier               string                 `protobuf:"bytes,1,opt,name=tier,proto3" json:"tier,omitempty"`
+	DailyLikes         uint64                 `protobuf:"varint,2,opt,name=daily_likes,json=dailyLikes,proto3" json:"daily_likes,omitempty"`
+	LikesUsed          uint64                 `protobuf:"varint,3,opt,name=likes_used,json=likesUsed,proto3" json:"likes_used,omitempty"`
+	LikesRemaining     uint64                 `protobuf:"varint,4,opt,name=likes_remaining,json=likesRemaining,proto3" json:"likes_remaining,omitempty"`
+	ResetUnixTimestamp uint64                 `protobuf:"varint,5,opt,name=reset_unix_timestamp,json=resetUnixTimestamp,proto3" json:"reset_unix_timestamp,omitempty"` // When likes_used goes back to 0
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetQuotaResponse) Reset() {
+	*x = GetQuotaResponse{}
+	mi := &file_explore_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuotaResponse) ProtoMessage() {}
+
+func (x *GetQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[11]
 	if x != nil {
-		return x.UnixTimestamp
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuotaResponse.ProtoReflect.Descriptor instead.
+func (*GetQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetQuotaResponse) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *GetQuotaResponse) GetDailyLikes() uint64 {
+	if x != nil {
+		return x.DailyLikes
 	}
 	return 0
 }
 
-var File_explore_service_proto protoreflect.FileDescriptor
+func (x *GetQuotaResponse) GetLikesUsed() uint64 {
+	if x != nil {
+		return x.LikesUsed
+	}
+	return 0
+}
 
-const file_explore_service_proto_rawDesc = "" +
-	"\n" +
-	"\x15explore-service.proto\x12\aexplore\"\x86\x01\n" +
-	"\x13ListLikedYouRequest\x12*\n" +
-	"\x11recipient_user_id\x18\x01 \x01(\tR\x0frecipientUserId\x12.\n" +
-	"\x10pagination_token\x18\x02 \x01(\tH\x00R\x0fpaginationToken\x88\x01\x01B\x13\n" +
-	"\x11_pagination_token\"\xf1\x01\n" +
-	"\x14ListLikedYouResponse\x12;\n" +
-	"\x06likers\x18\x01 \x03(\v2#.explore.ListLikedYouResponse.LikerR\x06likers\x127\n" +
-	"\x15next_pagination_token\x18\x02 \x01(\tH\x00R\x13nextPaginationToken\x88\x01\x01\x1aI\n" +
-	"\x05Liker\x12\x19\n" +
-	"\bactor_id\x18\x01 \x01(\tR\aactorId\x12%\n" +
-	"\x0eunix_timestamp\x18\x02 \x01(\x04R\runixTimestampB\x18\n" +
-	"\x16_next_pagination_token\"B\n" +
-	"\x14CountLikedYouRequest\x12*\n" +
-	"\x11recipient_user_id\x18\x01 \x01(\tR\x0frecipientUserId\"-\n" +
-	"\x15CountLikedYouResponse\x12\x14\n" +
-	"\x05count\x18\x01 \x01(\x04R\x05count\"\x8d\x01\n" +
-	"\x12PutDecisionRequest\x12\"\n" +
-	"\ractor_user_id\x18\x01 \x01(\tR\vactorUserId\x12*\n" +
-	"\x11recipient_user_id\x18\x02 \x01(\tR\x0frecipientUserId\x12'\n" +
-	"\x0fliked_recipient\x18\x03 \x01(\bR\x0elikedRecipient\"8\n" +
-	"\x13PutDecisionResponse\x12!\n" +
-	"\fmutual_likes\x18\x01 \x01(\bR\vmutualLikes2\xc7\x02\n" +
-	"\x0eExploreService\x12K\n" +
-	"\fListLikedYou\x12\x1c.explore.ListLikedYouRequest\x1a\x1d.explore.ListLikedYouResponse\x12N\n" +
-	"\x0fListNewLikedYou\x12\x1c.explore.ListLikedYouRequest\x1a\x1d.explore.ListLikedYouResponse\x12N\n" +
-	"\rCountLikedYou\x12\x1d.explore.CountLikedYouRequest\x1a\x1e.explore.CountLikedYouResponse\x12H\n" +
-	"\vPutDecision\x12\x1b.explore.PutDecisionRequest\x1a\x1c.explore.PutDecisionResponseb\x06proto3"
+func (x *GetQuotaResponse) GetLikesRemaining() uint64 {
+	if x != nil {
+		return x.LikesRemaining
+	}
+	return 0
+}
+
+func (x *GetQuotaResponse) GetResetUnixTimestamp() uint64 {
+	if x != nil {
+		return x.ResetUnixTimestamp
+	}
+	return 0
+}
+
+type BlockUserRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ActorUserId     string                 `protobuf:"bytes,1,opt,name=actor_user_id,json=actorUserId,proto3" json:"actor_user_id,omitempty"`
+	RecipientUserId string                 `protobuf:"bytes,2,opt,name=recipient_user_id,json=recipientUserId,proto3" json:"recipient_user_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BlockUserRequest) Reset() {
+	*x = BlockUserRequest{}
+	mi := &file_explore_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockUserRequest) ProtoMessage() {}
+
+func (x *BlockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockUserRequest.ProtoReflect.Descriptor instead.
+func (*BlockUserRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BlockUserRequest) GetActorUserId() string {
+	if x != nil {
+		return x.ActorUserId
+	}
+	return ""
+}
+
+func (x *BlockUserRequest) GetRecipientUserId() string {
+	if x != nil {
+		return x.RecipientUserId
+	}
+	return ""
+}
+
+type BlockUserResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MatchDissolved bool                   `protobuf:"varint,1,opt,name=match_dissolved,json=matchDissolved,proto3" json:"match_dissolved,omitempty"` // True if the users were matched before the block, always false when unblocking
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BlockUserResponse) Reset() {
+	*x = BlockUserResponse{}
+	mi := &file_explore_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockUserResponse) ProtoMessage() {}
+
+func (x *BlockUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockUserResponse.ProtoReflect.Descriptor instead.
+func (*BlockUserResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BlockUserResponse) GetMatchDissolved() bool {
+	if x != nil {
+		return x.MatchDissolved
+	}
+	return false
+}
+
+type ReportUserRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ActorUserId     string                 `protobuf:"bytes,1,opt,name=actor_user_id,json=actorUserId,proto3" json:"actor_user_id,omitempty"`
+	RecipientUserId string                 `protobuf:"bytes,2,opt,name=recipient_user_id,json=recipientUserId,proto3" json:"recipient_user_id,omitempty"`
+	Reason          string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Block           bool                   `protobuf:"varint,4,opt,name=block,proto3" json:"block,omitempty"` // Also block the recipient
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReportUserRequest) Reset() {
+	*x = ReportUserRequest{}
+	mi := &file_explore_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportUserRequest) ProtoMessage() {}
+
+func (x *ReportUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportUserRequest.ProtoReflect.Descriptor instead.
+func (*ReportUserRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReportUserRequest) GetActorUserId() string {
+	if x != nil {
+		return x.ActorUserId
+	}
+	return ""
+}
+
+func (x *ReportUserRequest) GetRecipientUserId() string {
+	if x != nil {
+		return x.RecipientUserId
+	}
+	return ""
+}
+
+func (x *ReportUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReportUserRequest) GetBlock() bool {
+	if x != nil {
+		return x.Block
+	}
+	return false
+}
+
+type ReportUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReportId      uint64                 `protobuf:"varint,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportUserResponse) Reset() {
+	*x = ReportUserResponse{}
+	mi := &file_explore_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportUserResponse) ProtoMessage() {}
+
+func (x *ReportUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportUserResponse.ProtoReflect.Descriptor instead.
+func (*ReportUserResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReportUserResponse) GetReportId() uint64 {
+	if x != nil {
+		return x.ReportId
+	}
+	return 0
+}
+
+type UnmatchRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ActorUserId     string                 `protobuf:"bytes,1,opt,name=actor_user_id,json=actorUserId,proto3" json:"actor_user_id,omitempty"`
+	RecipientUserId string                 `protobuf:"bytes,2,opt,name=recipient_user_id,json=recipientUserId,proto3" json:"recipient_user_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UnmatchRequest) Reset() {
+	*x = UnmatchRequest{}
+	mi := &file_explore_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnmatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnmatchRequest) ProtoMessage() {}
+
+func (x *UnmatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnmatchRequest.ProtoReflect.Descriptor instead.
+func (*UnmatchRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UnmatchRequest) GetActorUserId() string {
+	if x != nil {
+		return x.ActorUserId
+	}
+	return ""
+}
+
+func (x *UnmatchRequest) GetRecipientUserId() string {
+	if x != nil {
+		return x.RecipientUserId
+	}
+	return ""
+}
+
+type UnmatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Unmatched     bool                   `protobuf:"varint,1,opt,name=unmatched,proto3" json:"unmatched,omitempty"` // False if the users were not matched
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnmatchResponse) Reset() {
+	*x = UnmatchResponse{}
+	mi := &file_explore_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnmatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnmatchResponse) ProtoMessage() {}
+
+func (x *UnmatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnmatchResponse.ProtoReflect.Descriptor instead.
+func (*UnmatchResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UnmatchResponse) GetUnmatched() bool {
+	if x != nil {
+		return x.Unmatched
+	}
+	return false
+}
+
+type ListCandidatesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ActorUserId     string                 `protobuf:"bytes,1,opt,name=actor_user_id,json=actorUserId,proto3" json:"actor_user_id,omitempty"`
+	PaginationToken *string                `protobuf:"bytes,2,opt,name=pagination_token,json=paginationToken,proto3,oneof" json:"pagination_token,omitempty"`
+	Ranking         *string                `protobuf:"bytes,3,opt,name=ranking,proto3,oneof" json:"ranking,omitempty"` // One of recency, popularity, reciprocity or desirability, uses the server default if not set
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListCandidatesRequest) Reset() {
+	*x = ListCandidatesRequest{}
+	mi := &file_explore_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCandidatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCandidatesRequest) ProtoMessage() {}
+
+func (x *ListCandidatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCandidatesRequest.ProtoReflect.Descriptor instead.
+func (*ListCandidatesRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListCandidatesRequest) GetActorUserId() string {
+	if x != nil {
+		return x.ActorUserId
+	}
+	return ""
+}
+
+func (x *ListCandidatesRequest) GetPaginationToken() string {
+	if x != nil && x.PaginationToken != nil {
+		return *x.PaginationToken
+	}
+	return ""
+}
+
+func (x *ListCandidatesRequest) GetRanking() string {
+	if x != nil && x.Ranking != nil {
+		return *x.Ranking
+	}
+	return ""
+}
+
+type ListCandidatesResponse struct {
+	state               protoimpl.MessageState              `protogen:"open.v1"`
+	Candidates          []*ListCandidatesResponse_Candidate `protobuf:"bytes,1,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	NextPaginationToken *string                             `protobuf:"bytes,2,opt,name=next_pagination_token,json=nextPaginationToken,proto3,oneof" json:"next_pagination_token,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ListCandidatesResponse) Reset() {
+	*x = ListCandidatesResponse{}
+	mi := &file_explore_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCandidatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCandidatesResponse) ProtoMessage() {}
+
+func (x *ListCandidatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCandidatesResponse.ProtoReflect.Descriptor instead.
+func (*ListCandidatesResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListCandidatesResponse) GetCandidates() []*ListCandidatesResponse_Candidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *ListCandidatesResponse) GetNextPaginationToken() string {
+	if x != nil && x.NextPaginationToken != nil {
+		return *x.NextPaginationToken
+	}
+	return ""
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_explore_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WatchRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type LikeEvent struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Liker         *ListLikedYouResponse_Liker `protobuf:"bytes,1,opt,name=liker,proto3" json:"liker,omitempty"` // Redacted the same as ListLikedYou for users who can't see who liked them
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LikeEvent) Reset() {
+	*x = LikeEvent{}
+	mi := &file_explore_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LikeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LikeEvent) ProtoMessage() {}
+
+func (x *LikeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LikeEvent.ProtoReflect.Descriptor instead.
+func (*LikeEvent) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *LikeEvent) GetLiker() *ListLikedYouResponse_Liker {
+	if x != nil {
+		return x.Liker
+	}
+	return nil
+}
+
+type MatchEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // The other user in the match
+	UnixTimestamp uint64                 `protobuf:"varint,2,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchEvent) Reset() {
+	*x = MatchEvent{}
+	mi := &file_explore_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchEvent) ProtoMessage() {}
+
+func (x *MatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchEvent.ProtoReflect.Descriptor instead.
+func (*MatchEvent) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *MatchEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MatchEvent) GetUnixTimestamp() uint64 {
+	if x != nil {
+		return x.UnixTimestamp
+	}
+	return 0
+}
+
+type ListDesirabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         *uint32                `protobuf:"varint,1,opt,name=limit,proto3,oneof" json:"limit,omitempty"` // Only return the top scores, every user is returned if not set
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDesirabilityRequest) Reset() {
+	*x = ListDesirabilityRequest{}
+	mi := &file_explore_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDesirabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDesirabilityRequest) ProtoMessage() {}
+
+func (x *ListDesirabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDesirabilityRequest.ProtoReflect.Descriptor instead.
+func (*ListDesirabilityRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListDesirabilityRequest) GetLimit() uint32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+type ListDesirabilityResponse struct {
+	state             protoimpl.MessageState            `protogen:"open.v1"`
+	Scores            []*ListDesirabilityResponse_Score `protobuf:"bytes,1,rep,name=scores,proto3" json:"scores,omitempty"`
+	Mean              float64                           `protobuf:"fixed64,2,opt,name=mean,proto3" json:"mean,omitempty"`
+	StandardDeviation float64                           `protobuf:"fixed64,3,opt,name=standard_deviation,json=standardDeviation,proto3" json:"standard_deviation,omitempty"`
+	Min               float64                           `protobuf:"fixed64,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max               float64                           `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListDesirabilityResponse) Reset() {
+	*x = ListDesirabilityResponse{}
+	mi := &file_explore_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDesirabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDesirabilityResponse) ProtoMessage() {}
+
+func (x *ListDesirabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDesirabilityResponse.ProtoReflect.Descriptor instead.
+func (*ListDesirabilityResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListDesirabilityResponse) GetScores() []*ListDesirabilityResponse_Score {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *ListDesirabilityResponse) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *ListDesirabilityResponse) GetStandardDeviation() float64 {
+	if x != nil {
+		return x.StandardDeviation
+	}
+	return 0
+}
+
+func (x *ListDesirabilityResponse) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *ListDesirabilityResponse) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type RecomputeDesirabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecomputeDesirabilityRequest) Reset() {
+	*x = RecomputeDesirabilityRequest{}
+	mi := &file_explore_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecomputeDesirabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecomputeDesirabilityRequest) ProtoMessage() {}
+
+func (x *RecomputeDesirabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecomputeDesirabilityRequest.ProtoReflect.Descriptor instead.
+func (*RecomputeDesirabilityRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{25}
+}
+
+type RecomputeDesirabilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         uint64                 `protobuf:"varint,1,opt,name=users,proto3" json:"users,omitempty"`
+	Decisions     uint64                 `protobuf:"varint,2,opt,name=decisions,proto3" json:"decisions,omitempty"`
+	MaxDrift      float64                `protobuf:"fixed64,3,opt,name=max_drift,json=maxDrift,proto3" json:"max_drift,omitempty"` // Largest difference between a stored score and its recomputed score
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecomputeDesirabilityResponse) Reset() {
+	*x = RecomputeDesirabilityResponse{}
+	mi := &file_explore_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecomputeDesirabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecomputeDesirabilityResponse) ProtoMessage() {}
+
+func (x *RecomputeDesirabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecomputeDesirabilityResponse.ProtoReflect.Descriptor instead.
+func (*RecomputeDesirabilityResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RecomputeDesirabilityResponse) GetUsers() uint64 {
+	if x != nil {
+		return x.Users
+	}
+	return 0
+}
+
+func (x *RecomputeDesirabilityResponse) GetDecisions() uint64 {
+	if x != nil {
+		return x.Decisions
+	}
+	return 0
+}
+
+func (x *RecomputeDesirabilityResponse) GetMaxDrift() float64 {
+	if x != nil {
+		return x.MaxDrift
+	}
+	return 0
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`                           // Used to sign every request with HMAC-SHA256, never returned
+	EventTypes    []string               `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"` // Any of MatchCreated and UnmatchRecorded
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookSubscriptionRequest) Reset() {
+	*x = CreateWebhookSubscriptionRequest{}
+	mi := &file_explore_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateWebhookSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateWebhookSubscriptionRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookSubscriptionRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *CreateWebhookSubscriptionRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+type WebhookSubscription struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	UnixTimestamp uint64                 `protobuf:"varint,4,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookSubscription) Reset() {
+	*x = WebhookSubscription{}
+	mi := &file_explore_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookSubscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookSubscription) ProtoMessage() {}
+
+func (x *WebhookSubscription) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookSubscription.ProtoReflect.Descriptor instead.
+func (*WebhookSubscription) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WebhookSubscription) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WebhookSubscription) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookSubscription) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *WebhookSubscription) GetUnixTimestamp() uint64 {
+	if x != nil {
+		return x.UnixTimestamp
+	}
+	return 0
+}
+
+type ListWebhookSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookSubscriptionsRequest) Reset() {
+	*x = ListWebhookSubscriptionsRequest{}
+	mi := &file_explore_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListWebhookSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{29}
+}
+
+type ListWebhookSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*WebhookSubscription `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookSubscriptionsResponse) Reset() {
+	*x = ListWebhookSubscriptionsResponse{}
+	mi := &file_explore_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListWebhookSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListWebhookSubscriptionsResponse) GetSubscriptions() []*WebhookSubscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type DeleteWebhookSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookSubscriptionRequest) Reset() {
+	*x = DeleteWebhookSubscriptionRequest{}
+	mi := &file_explore_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookSubscriptionRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeleteWebhookSubscriptionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteWebhookSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"` // False if there was no active subscription with the id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookSubscriptionResponse) Reset() {
+	*x = DeleteWebhookSubscriptionResponse{}
+	mi := &file_explore_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookSubscriptionResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteWebhookSubscriptionResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type ListWebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FailedOnly    bool                   `protobuf:"varint,1,opt,name=failed_only,json=failedOnly,proto3" json:"failed_only,omitempty"` // Only list deliveries that ran out of attempts
+	Limit         *uint32                `protobuf:"varint,2,opt,name=limit,proto3,oneof" json:"limit,omitempty"`                       // Defaults to 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_explore_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetFailedOnly() bool {
+	if x != nil {
+		return x.FailedOnly
+	}
+	return false
+}
+
+func (x *ListWebhookDeliveriesRequest) GetLimit() uint32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState                    `protogen:"open.v1"`
+	Deliveries    []*ListWebhookDeliveriesResponse_Delivery `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_explore_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*ListWebhookDeliveriesResponse_Delivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type ReplayWebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryIds   []uint64               `protobuf:"varint,1,rep,packed,name=delivery_ids,json=deliveryIds,proto3" json:"delivery_ids,omitempty"`
+	AllFailed     bool                   `protobuf:"varint,2,opt,name=all_failed,json=allFailed,proto3" json:"all_failed,omitempty"` // Replay every failed delivery as well as the ones in delivery_ids
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayWebhookDeliveriesRequest) Reset() {
+	*x = ReplayWebhookDeliveriesRequest{}
+	mi := &file_explore_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ReplayWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ReplayWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ReplayWebhookDeliveriesRequest) GetDeliveryIds() []uint64 {
+	if x != nil {
+		return x.DeliveryIds
+	}
+	return nil
+}
+
+func (x *ReplayWebhookDeliveriesRequest) GetAllFailed() bool {
+	if x != nil {
+		return x.AllFailed
+	}
+	return false
+}
+
+type ReplayWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Replayed      uint64                 `protobuf:"varint,1,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayWebhookDeliveriesResponse) Reset() {
+	*x = ReplayWebhookDeliveriesResponse{}
+	mi := &file_explore_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ReplayWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ReplayWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ReplayWebhookDeliveriesResponse) GetReplayed() uint64 {
+	if x != nil {
+		return x.Replayed
+	}
+	return 0
+}
+
+type GetCacheStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCacheStatsRequest) Reset() {
+	*x = GetCacheStatsRequest{}
+	mi := &file_explore_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCacheStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCacheStatsRequest) ProtoMessage() {}
+
+func (x *GetCacheStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCacheStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetCacheStatsRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{37}
+}
+
+type GetCacheStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backend       string                 `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"` // memory, redis or none
+	Hits          uint64                 `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses        uint64                 `protobuf:"varint,3,opt,name=misses,proto3" json:"misses,omitempty"`
+	HitRate       float64                `protobuf:"fixed64,4,opt,name=hit_rate,json=hitRate,proto3" json:"hit_rate,omitempty"` // Hits out of every read, 0 if there has been no reads
+	Entries       *uint64                `protobuf:"varint,5,opt,name=entries,proto3,oneof" json:"entries,omitempty"`           // How many entries are cached, only set for the memory cache
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCacheStatsResponse) Reset() {
+	*x = GetCacheStatsResponse{}
+	mi := &file_explore_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCacheStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCacheStatsResponse) ProtoMessage() {}
+
+func (x *GetCacheStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCacheStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetCacheStatsResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetCacheStatsResponse) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *GetCacheStatsResponse) GetHits() uint64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *GetCacheStatsResponse) GetMisses() uint64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+func (x *GetCacheStatsResponse) GetHitRate() float64 {
+	if x != nil {
+		return x.HitRate
+	}
+	return 0
+}
+
+func (x *GetCacheStatsResponse) GetEntries() uint64 {
+	if x != nil && x.Entries != nil {
+		return *x.Entries
+	}
+	return 0
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tier          *string                `protobuf:"bytes,1,opt,name=tier,proto3,oneof" json:"tier,omitempty"` // Only list users on this tier, free or premium
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_explore_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListUsersRequest) GetTier() string {
+	if x != nil && x.Tier != nil {
+		return *x.Tier
+	}
+	return ""
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Users         []*ListUsersResponse_User `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_explore_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListUsersResponse) GetUsers() []*ListUsersResponse_User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type ListLikedYouResponse_Liker struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorId       string                 `protobuf:"bytes,1,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	UnixTimestamp uint64                 `protobuf:"varint,2,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
+	Redacted      bool                   `protobuf:"varint,3,opt,name=redacted,proto3" json:"redacted,omitempty"` // True if the actor is hidden from the recipient, actor_id is empty and unix_timestamp is only to the day
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLikedYouResponse_Liker) Reset() {
+	*x = ListLikedYouResponse_Liker{}
+	mi := &file_explore_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLikedYouResponse_Liker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLikedYouResponse_Liker) ProtoMessage() {}
+
+func (x *ListLikedYouResponse_Liker) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLikedYouResponse_Liker.ProtoReflect.Descriptor instead.
+func (*ListLikedYouResponse_Liker) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *ListLikedYouResponse_Liker) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *ListLikedYouResponse_Liker) GetUnixTimestamp() uint64 {
+	if x != nil {
+		return x.UnixTimestamp
+	}
+	return 0
+}
+
+func (x *ListLikedYouResponse_Liker) GetRedacted() bool {
+	if x != nil {
+		return x.Redacted
+	}
+	return false
+}
+
+type CountLikedYouResponse_Breakdown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         uint64                 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`           // Same as count
+	Unanswered    uint64                 `protobuf:"varint,2,opt,name=unanswered,proto3" json:"unanswered,omitempty"` // Likes the recipient has not liked or passed on yet
+	Passed        uint64                 `protobuf:"varint,3,opt,name=passed,proto3" json:"passed,omitempty"`         // Likes the recipient passed on
+	Matches       uint64                 `protobuf:"varint,4,opt,name=matches,proto3" json:"matches,omitempty"`       // Likes the recipient liked back
+	Since         *uint64                `protobuf:"varint,5,opt,name=since,proto3,oneof" json:"since,omitempty"`     // Likes made after since_unix_timestamp, only set if it was given
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountLikedYouResponse_Breakdown) Reset() {
+	*x = CountLikedYouResponse_Breakdown{}
+	mi := &file_explore_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountLikedYouResponse_Breakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountLikedYouResponse_Breakdown) ProtoMessage() {}
+
+func (x *CountLikedYouResponse_Breakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountLikedYouResponse_Breakdown.ProtoReflect.Descriptor instead.
+func (*CountLikedYouResponse_Breakdown) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *CountLikedYouResponse_Breakdown) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *CountLikedYouResponse_Breakdown) GetUnanswered() uint64 {
+	if x != nil {
+		return x.Unanswered
+	}
+	return 0
+}
+
+func (x *CountLikedYouResponse_Breakdown) GetPassed() uint64 {
+	if x != nil {
+		return x.Passed
+	}
+	return 0
+}
+
+func (x *CountLikedYouResponse_Breakdown) GetMatches() uint64 {
+	if x != nil {
+		return x.Matches
+	}
+	return 0
+}
+
+func (x *CountLikedYouResponse_Breakdown) GetSince() uint64 {
+	if x != nil && x.Since != nil {
+		return *x.Since
+	}
+	return 0
+}
+
+type PutDecisionsResponse_Result struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MutualLikes   bool                   `protobuf:"varint,1,opt,name=mutual_likes,json=mutualLikes,proto3" json:"mutual_likes,omitempty"`
+	ErrorCode     *uint32                `protobuf:"varint,2,opt,name=error_code,json=errorCode,proto3,oneof" json:"error_code,omitempty"` // gRPC status code, only set if this decision failed and was not recorded
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Replayed      bool                   `protobuf:"varint,4,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutDecisionsResponse_Result) Reset() {
+	*x = PutDecisionsResponse_Result{}
+	mi := &file_explore_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutDecisionsResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutDecisionsResponse_Result) ProtoMessage() {}
+
+func (x *PutDecisionsResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutDecisionsResponse_Result.ProtoReflect.Descriptor instead.
+func (*PutDecisionsResponse_Result) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *PutDecisionsResponse_Result) GetMutualLikes() bool {
+	if x != nil {
+		return x.MutualLikes
+	}
+	return false
+}
+
+func (x *PutDecisionsResponse_Result) GetErrorCode() uint32 {
+	if x != nil && x.ErrorCode != nil {
+		return *x.ErrorCode
+	}
+	return 0
+}
+
+func (x *PutDecisionsResponse_Result) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *PutDecisionsResponse_Result) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+type ListCandidatesResponse_Candidate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UnixTimestamp uint64                 `protobuf:"varint,2,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"` // When the candidate joined
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCandidatesResponse_Candidate) Reset() {
+	*x = ListCandidatesResponse_Candidate{}
+	mi := &file_explore_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCandidatesResponse_Candidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCandidatesResponse_Candidate) ProtoMessage() {}
+
+func (x *ListCandidatesResponse_Candidate) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCandidatesResponse_Candidate.ProtoReflect.Descriptor instead.
+func (*ListCandidatesResponse_Candidate) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{19, 0}
+}
+
+func (x *ListCandidatesResponse_Candidate) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListCandidatesResponse_Candidate) GetUnixTimestamp() uint64 {
+	if x != nil {
+		return x.UnixTimestamp
+	}
+	return 0
+}
+
+type ListDesirabilityResponse_Score struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Desirability   float64                `protobuf:"fixed64,2,opt,name=desirability,proto3" json:"desirability,omitempty"`
+	LikesReceived  uint64                 `protobuf:"varint,3,opt,name=likes_received,json=likesReceived,proto3" json:"likes_received,omitempty"`
+	PassesReceived uint64                 `protobuf:"varint,4,opt,name=passes_received,json=passesReceived,proto3" json:"passes_received,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListDesirabilityResponse_Score) Reset() {
+	*x = ListDesirabilityResponse_Score{}
+	mi := &file_explore_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDesirabilityResponse_Score) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDesirabilityResponse_Score) ProtoMessage() {}
+
+func (x *ListDesirabilityResponse_Score) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDesirabilityResponse_Score.ProtoReflect.Descriptor instead.
+func (*ListDesirabilityResponse_Score) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{24, 0}
+}
+
+func (x *ListDesirabilityResponse_Score) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListDesirabilityResponse_Score) GetDesirability() float64 {
+	if x != nil {
+		return x.Desirability
+	}
+	return 0
+}
+
+func (x *ListDesirabilityResponse_Score) GetLikesReceived() uint64 {
+	if x != nil {
+		return x.LikesReceived
+	}
+	return 0
+}
+
+func (x *ListDesirabilityResponse_Score) GetPassesReceived() uint64 {
+	if x != nil {
+		return x.PassesReceived
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse_Attempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UnixTimestamp uint64                 `protobuf:"varint,1,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
+	StatusCode    *uint32                `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3,oneof" json:"status_code,omitempty"` // Not set if there was no response
+	Error         *string                `protobuf:"bytes,3,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	DurationMs    uint64                 `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse_Attempt) Reset() {
+	*x = ListWebhookDeliveriesResponse_Attempt{}
+	mi := &file_explore_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse_Attempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse_Attempt) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse_Attempt) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse_Attempt.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse_Attempt) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{34, 0}
+}
+
+func (x *ListWebhookDeliveriesResponse_Attempt) GetUnixTimestamp() uint64 {
+	if x != nil {
+		return x.UnixTimestamp
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesResponse_Attempt) GetStatusCode() uint32 {
+	if x != nil && x.StatusCode != nil {
+		return *x.StatusCode
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesResponse_Attempt) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesResponse_Attempt) GetDurationMs() uint64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse_Delivery struct {
+	state          protoimpl.MessageState                   `protogen:"open.v1"`
+	Id             uint64                                   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionId uint64                                   `protobuf:"varint,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	EventType      string                                   `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	UnixTimestamp  uint64                                   `protobuf:"varint,4,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"`
+	Delivered      bool                                     `protobuf:"varint,5,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Failed         bool                                     `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"` // True if the delivery ran out of attempts and won't be retried
+	Attempts       []*ListWebhookDeliveriesResponse_Attempt `protobuf:"bytes,7,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) Reset() {
+	*x = ListWebhookDeliveriesResponse_Delivery{}
+	mi := &file_explore_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse_Delivery) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse_Delivery.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse_Delivery) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{34, 1}
+}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) GetUnixTimestamp() uint64 {
+	if x != nil {
+		return x.UnixTimestamp
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) GetDelivered() bool {
+	if x != nil {
+		return x.Delivered
+	}
+	return false
+}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) GetFailed() bool {
+	if x != nil {
+		return x.Failed
+	}
+	return false
+}
+
+func (x *ListWebhookDeliveriesResponse_Delivery) GetAttempts() []*ListWebhookDeliveriesResponse_Attempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+type ListUsersResponse_User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Tier          string                 `protobuf:"bytes,2,opt,name=tier,proto3" json:"tier,omitempty"`
+	UnixTimestamp uint64                 `protobuf:"varint,3,opt,name=unix_timestamp,json=unixTimestamp,proto3" json:"unix_timestamp,omitempty"` // When the user was created
+	Desirability  float64                `protobuf:"fixed64,4,opt,name=desirability,proto3" json:"desirability,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse_User) Reset() {
+	*x = ListUsersResponse_User{}
+	mi := &file_explore_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse_User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse_User) ProtoMessage() {}
+
+func (x *ListUsersResponse_User) ProtoReflect() protoreflect.Message {
+	mi := &file_explore_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse_User.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse_User) Descriptor() ([]byte, []int) {
+	return file_explore_service_proto_rawDescGZIP(), []int{40, 0}
+}
+
+func (x *ListUsersResponse_User) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListUsersResponse_User) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *ListUsersResponse_User) GetUnixTimestamp() uint64 {
+	if x != nil {
+		return x.UnixTimestamp
+	}
+	return 0
+}
+
+func (x *ListUsersResponse_User) GetDesirability() float64 {
+	if x != nil {
+		return x.Desirability
+	}
+	return 0
+}
+
+var File_explore_service_proto protoreflect.FileDescriptor
+
+const file_explore_service_proto_rawDesc = "" +
+	"\n" +
+	"\x15explore-service.proto\x12\aexplore\"\x86\x01\n" +
+	"\x13ListLikedYouRequest\x12*\n" +
+	"\x11recipient_user_id\x18\x01 \x01(\tR\x0frecipientUserId\x12.\n" +
+	"\x10pagination_token\x18\x02 \x01(\tH\x00R\x0fpaginationToken\x88\x01\x01B\x13\n" +
+	"\x11_pagination_token\"\xc3\x02\n" +
+	"\x14ListLikedYouResponse\x12;\n" +
+	"\x06likers\x18\x01 \x03(\v2#.explore.ListLikedYouResponse.LikerR\x06likers\x127\n" +
+	"\x15next_pagination_token\x18\x02 \x01(\tH\x00R\x13nextPaginationToken\x88\x01\x01\x12$\n" +
+	"\vtotal_count\x18\x03 \x01(\x04H\x01R\n" +
+	"totalCount\x88\x01\x01\x1ae\n" +
+	"\x05Liker\x12\x19\n" +
+	"\bactor_id\x18\x01 \x01(\tR\aactorId\x12%\n" +
+	"\x0eunix_timestamp\x18\x02 \x01(\x04R\runixTimestamp\x12\x1a\n" +
+	"\bredacted\x18\x03 \x01(\bR\bredactedB\x18\n" +
+	"\x16_next_pagination_tokenB\x0e\n" +
+	"\f_total_count\"-\n" +
+	"\x12ListMatchesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"D\n" +
+	"\x13ListMatchesResponse\x12-\n" +
+	"\amatches\x18\x01 \x03(\v2\x13.explore.MatchEventR\amatches\"\xbf\x01\n" +
+	"\x14CountLikedYouRequest\x12*\n" +
+	"\x11recipient_user_id\x18\x01 \x01(\tR\x0frecipientUserId\x12+\n" +
+	"\x11include_breakdown\x18\x02 \x01(\bR\x10includeBreakdown\x125\n" +
+	"\x14since_unix_timestamp\x18\x03 \x01(\x04H\x00R\x12sinceUnixTimestamp\x88\x01\x01B\x17\n" +
+	"\x15_since_unix_timestamp\"\xa3\x02\n" +
+	"\x15CountLikedYouResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x04R\x05count\x12K\n" +
+	"\tbreakdown\x18\x02 \x01(\v2(.explore.CountLikedYouResponse.BreakdownH\x00R\tbreakdown\x88\x01\x01\x1a\x98\x01\n" +
+	"\tBreakdown\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x04R\x05total\x12\x1e\n" +
+	"\n" +
+	"unanswered\x18\x02 \x01(\x04R\n" +
+	"unanswered\x12\x16\n" +
+	"\x06passed\x18\x03 \x01(\x04R\x06passed\x12\x18\n" +
+	"\amatches\x18\x04 \x01(\x04R\amatches\x12\x19\n" +
+	"\x05since\x18\x05 \x01(\x04H\x00R\x05since\x88\x01\x01B\b\n" +
+	"\x06_sinceB\f\n" +
+	"\n" +
+	"_breakdown\"\xcf\x01\n" +
+	"\x12PutDecisionRequest\x12\"\n" +
+	"\ractor_user_id\x18\x01 \x01(\tR\vactorUserId\x12*\n" +
+	"\x11recipient_user_id\x18\x02 \x01(\tR\x0frecipientUserId\x12'\n" +
+	"\x0fliked_recipient\x18\x03 \x01(\bR\x0elikedRecipient\x12,\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tH\x00R\x0eidempotencyKey\x88\x01\x01B\x12\n" +
+	"\x10_idempotency_key\"T\n" +
+	"\x13PutDecisionResponse\x12!\n" +
+	"\fmutual_likes\x18\x01 \x01(\bR\vmutualLikes\x12\x1a\n" +
+	"\breplayed\x18\x02 \x01(\bR\breplayed\"h\n" +
+	"\x13PutDecisionsRequest\x129\n" +
+	"\tdecisions\x18\x01 \x03(\v2\x1b.explore.PutDecisionRequestR\tdecisions\x12\x16\n" +
+	"\x06atomic\x18\x02 \x01(\bR\x06atomic\"\xf8\x01\n" +
+	"\x14PutDecisionsResponse\x12>\n" +
+	"\aresults\x18\x01 \x03(\v2$.explore.PutDecisionsResponse.ResultR\aresults\x1a\x9f\x01\n" +
+	"\x06Result\x12!\n" +
+	"\fmutual_likes\x18\x01 \x01(\bR\vmutualLikes\x12\"\n" +
+	"\n" +
+	"error_code\x18\x02 \x01(\rH\x00R\terrorCode\x88\x01\x01\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\x12\x1a\n" +
+	"\breplayed\x18\x04 \x01(\bR\breplayedB\r\n" +
+	"\v_error_code\"*\n" +
+	"\x0fGetQuotaRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xc1\x01\n" +
+	"\x10GetQuotaResponse\x12\x12\n" +
+	"\x04tier\x18\x01 \x01(\tR\x04tier\x12\x1f\n" +
+	"\vdaily_likes\x18\x02 \x01(\x04R\n" +
+	"dailyLikes\x12\x1d\n" +
+	"\n" +
+	"likes_used\x18\x03 \x01(\x04R\tlikesUsed\x12'\n" +
+	"\x0flikes_remaining\x18\x04 \x01(\x04R\x0elikesRemaining\x120\n" +
+	"\x14reset_unix_timestamp\x18\x05 \x01(\x04R\x12resetUnixTimestamp\"b\n" +
+	"\x10BlockUserRequest\x12\"\n" +
+	"\ractor_user_id\x18\x01 \x01(\tR\vactorUserId\x12*\n" +
+	"\x11recipient_user_id\x18\x02 \x01(\tR\x0frecipientUserId\"<\n" +
+	"\x11BlockUserResponse\x12'\n" +
+	"\x0fmatch_dissolved\x18\x01 \x01(\bR\x0ematchDissolved\"\x91\x01\n" +
+	"\x11ReportUserRequest\x12\"\n" +
+	"\ractor_user_id\x18\x01 \x01(\tR\vactorUserId\x12*\n" +
+	"\x11recipient_user_id\x18\x02 \x01(\tR\x0frecipientUserId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05block\x18\x04 \x01(\bR\x05block\"1\n" +
+	"\x12ReportUserResponse\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\x04R\breportId\"`\n" +
+	"\x0eUnmatchRequest\x12\"\n" +
+	"\ractor_user_id\x18\x01 \x01(\tR\vactorUserId\x12*\n" +
+	"\x11recipient_user_id\x18\x02 \x01(\tR\x0frecipientUserId\"/\n" +
+	"\x0fUnmatchResponse\x12\x1c\n" +
+	"\tunmatched\x18\x01 \x01(\bR\tunmatched\"\xab\x01\n" +
+	"\x15ListCandidatesRequest\x12\"\n" +
+	"\ractor_user_id\x18\x01 \x01(\tR\vactorUserId\x12.\n" +
+	"\x10pagination_token\x18\x02 \x01(\tH\x00R\x0fpaginationToken\x88\x01\x01\x12\x1d\n" +
+	"\aranking\x18\x03 \x01(\tH\x01R\aranking\x88\x01\x01B\x13\n" +
+	"\x11_pagination_tokenB\n" +
+	"\n" +
+	"\b_ranking\"\x89\x02\n" +
+	"\x16ListCandidatesResponse\x12I\n" +
+	"\n" +
+	"candidates\x18\x01 \x03(\v2).explore.ListCandidatesResponse.CandidateR\n" +
+	"candidates\x127\n" +
+	"\x15next_pagination_token\x18\x02 \x01(\tH\x00R\x13nextPaginationToken\x88\x01\x01\x1aQ\n" +
+	"\tCandidate\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0eunix_timestamp\x18\x02 \x01(\x04R\runixTimestampJ\x04\b\x03\x10\x04B\x18\n" +
+	"\x16_next_pagination_token\"'\n" +
+	"\fWatchRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"F\n" +
+	"\tLikeEvent\x129\n" +
+	"\x05liker\x18\x01 \x01(\v2#.explore.ListLikedYouResponse.LikerR\x05liker\"L\n" +
+	"\n" +
+	"MatchEvent\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0eunix_timestamp\x18\x02 \x01(\x04R\runixTimestamp\">\n" +
+	"\x17ListDesirabilityRequest\x12\x19\n" +
+	"\x05limit\x18\x01 \x01(\rH\x00R\x05limit\x88\x01\x01B\b\n" +
+	"\x06_limit\"\xd9\x02\n" +
+	"\x18ListDesirabilityResponse\x12?\n" +
+	"\x06scores\x18\x01 \x03(\v2'.explore.ListDesirabilityResponse.ScoreR\x06scores\x12\x12\n" +
+	"\x04mean\x18\x02 \x01(\x01R\x04mean\x12-\n" +
+	"\x12standard_deviation\x18\x03 \x01(\x01R\x11standardDeviation\x12\x10\n" +
+	"\x03min\x18\x04 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x05 \x01(\x01R\x03max\x1a\x94\x01\n" +
+	"\x05Score\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\"\n" +
+	"\fdesirability\x18\x02 \x01(\x01R\fdesirability\x12%\n" +
+	"\x0elikes_received\x18\x03 \x01(\x04R\rlikesReceived\x12'\n" +
+	"\x0fpasses_received\x18\x04 \x01(\x04R\x0epassesReceived\"\x1e\n" +
+	"\x1cRecomputeDesirabilityRequest\"p\n" +
+	"\x1dRecomputeDesirabilityResponse\x12\x14\n" +
+	"\x05users\x18\x01 \x01(\x04R\x05users\x12\x1c\n" +
+	"\tdecisions\x18\x02 \x01(\x04R\tdecisions\x12\x1b\n" +
+	"\tmax_drift\x18\x03 \x01(\x01R\bmaxDrift\"m\n" +
+	" CreateWebhookSubscriptionRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\"\x7f\n" +
+	"\x13WebhookSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\x12%\n" +
+	"\x0eunix_timestamp\x18\x04 \x01(\x04R\runixTimestamp\"!\n" +
+	"\x1fListWebhookSubscriptionsRequest\"f\n" +
+	" ListWebhookSubscriptionsResponse\x12B\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1c.explore.WebhookSubscriptionR\rsubscriptions\"2\n" +
+	" DeleteWebhookSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"=\n" +
+	"!DeleteWebhookSubscriptionResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"d\n" +
+	"\x1cListWebhookDeliveriesRequest\x12\x1f\n" +
+	"\vfailed_only\x18\x01 \x01(\bR\n" +
+	"failedOnly\x12\x19\n" +
+	"\x05limit\x18\x02 \x01(\rH\x00R\x05limit\x88\x01\x01B\b\n" +
+	"\x06_limit\"\xad\x04\n" +
+	"\x1dListWebhookDeliveriesResponse\x12O\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2/.explore.ListWebhookDeliveriesResponse.DeliveryR\n" +
+	"deliveries\x1a\xac\x01\n" +
+	"\aAttempt\x12%\n" +
+	"\x0eunix_timestamp\x18\x01 \x01(\x04R\runixTimestamp\x12$\n" +
+	"\vstatus_code\x18\x02 \x01(\rH\x00R\n" +
+	"statusCode\x88\x01\x01\x12\x19\n" +
+	"\x05error\x18\x03 \x01(\tH\x01R\x05error\x88\x01\x01\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x04R\n" +
+	"durationMsB\x0e\n" +
+	"\f_status_codeB\b\n" +
+	"\x06_error\x1a\x8b\x02\n" +
+	"\bDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\x04R\x0esubscriptionId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\x12%\n" +
+	"\x0eunix_timestamp\x18\x04 \x01(\x04R\runixTimestamp\x12\x1c\n" +
+	"\tdelivered\x18\x05 \x01(\bR\tdelivered\x12\x16\n" +
+	"\x06failed\x18\x06 \x01(\bR\x06failed\x12J\n" +
+	"\battempts\x18\a \x03(\v2..explore.ListWebhookDeliveriesResponse.AttemptR\battempts\"b\n" +
+	"\x1eReplayWebhookDeliveriesRequest\x12!\n" +
+	"\fdelivery_ids\x18\x01 \x03(\x04R\vdeliveryIds\x12\x1d\n" +
+	"\n" +
+	"all_failed\x18\x02 \x01(\bR\tallFailed\"=\n" +
+	"\x1fReplayWebhookDeliveriesResponse\x12\x1a\n" +
+	"\breplayed\x18\x01 \x01(\x04R\breplayed\"\x16\n" +
+	"\x14GetCacheStatsRequest\"\xa3\x01\n" +
+	"\x15GetCacheStatsResponse\x12\x18\n" +
+	"\abackend\x18\x01 \x01(\tR\abackend\x12\x12\n" +
+	"\x04hits\x18\x02 \x01(\x04R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x03 \x01(\x04R\x06misses\x12\x19\n" +
+	"\bhit_rate\x18\x04 \x01(\x01R\ahitRate\x12\x1d\n" +
+	"\aentries\x18\x05 \x01(\x04H\x00R\aentries\x88\x01\x01B\n" +
+	"\n" +
+	"\b_entries\"4\n" +
+	"\x10ListUsersRequest\x12\x17\n" +
+	"\x04tier\x18\x01 \x01(\tH\x00R\x04tier\x88\x01\x01B\a\n" +
+	"\x05_tier\"\xca\x01\n" +
+	"\x11ListUsersResponse\x125\n" +
+	"\x05users\x18\x01 \x03(\v2\x1f.explore.ListUsersResponse.UserR\x05users\x1a~\n" +
+	"\x04User\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04tier\x18\x02 \x01(\tR\x04tier\x12%\n" +
+	"\x0eunix_timestamp\x18\x03 \x01(\x04R\runixTimestamp\x12\"\n" +
+	"\fdesirability\x18\x04 \x01(\x01R\fdesirability2\xfa\a\n" +
+	"\x0eExploreService\x12K\n" +
+	"\fListLikedYou\x12\x1c.explore.ListLikedYouRequest\x1a\x1d.explore.ListLikedYouResponse\x12N\n" +
+	"\x0fListNewLikedYou\x12\x1c.explore.ListLikedYouRequest\x1a\x1d.explore.ListLikedYouResponse\x12H\n" +
+	"\vListMatches\x12\x1b.explore.ListMatchesRequest\x1a\x1c.explore.ListMatchesResponse\x12N\n" +
+	"\rCountLikedYou\x12\x1d.explore.CountLikedYouRequest\x1a\x1e.explore.CountLikedYouResponse\x12H\n" +
+	"\vPutDecision\x12\x1b.explore.PutDecisionRequest\x1a\x1c.explore.PutDecisionResponse\x12K\n" +
+	"\fPutDecisions\x12\x1c.explore.PutDecisionsRequest\x1a\x1d.explore.PutDecisionsResponse\x12?\n" +
+	"\bGetQuota\x12\x18.explore.GetQuotaRequest\x1a\x19.explore.GetQuotaResponse\x12B\n" +
+	"\tBlockUser\x12\x19.explore.BlockUserRequest\x1a\x1a.explore.BlockUserResponse\x12D\n" +
+	"\vUnblockUser\x12\x19.explore.BlockUserRequest\x1a\x1a.explore.BlockUserResponse\x12E\n" +
+	"\n" +
+	"ReportUser\x12\x1a.explore.ReportUserRequest\x1a\x1b.explore.ReportUserResponse\x12<\n" +
+	"\aUnmatch\x12\x17.explore.UnmatchRequest\x1a\x18.explore.UnmatchResponse\x12Q\n" +
+	"\x0eListCandidates\x12\x1e.explore.ListCandidatesRequest\x1a\x1f.explore.ListCandidatesResponse\x129\n" +
+	"\n" +
+	"WatchLikes\x12\x15.explore.WatchRequest\x1a\x12.explore.LikeEvent0\x01\x12<\n" +
+	"\fWatchMatches\x12\x15.explore.WatchRequest\x1a\x13.explore.MatchEvent0\x012\x84\a\n" +
+	"\fAdminService\x12W\n" +
+	"\x10ListDesirability\x12 .explore.ListDesirabilityRequest\x1a!.explore.ListDesirabilityResponse\x12f\n" +
+	"\x15RecomputeDesirability\x12%.explore.RecomputeDesirabilityRequest\x1a&.explore.RecomputeDesirabilityResponse\x12d\n" +
+	"\x19CreateWebhookSubscription\x12).explore.CreateWebhookSubscriptionRequest\x1a\x1c.explore.WebhookSubscription\x12o\n" +
+	"\x18ListWebhookSubscriptions\x12(.explore.ListWebhookSubscriptionsRequest\x1a).explore.ListWebhookSubscriptionsResponse\x12r\n" +
+	"\x19DeleteWebhookSubscription\x12).explore.DeleteWebhookSubscriptionRequest\x1a*.explore.DeleteWebhookSubscriptionResponse\x12f\n" +
+	"\x15ListWebhookDeliveries\x12%.explore.ListWebhookDeliveriesRequest\x1a&.explore.ListWebhookDeliveriesResponse\x12l\n" +
+	"\x17ReplayWebhookDeliveries\x12'.explore.ReplayWebhookDeliveriesRequest\x1a(.explore.ReplayWebhookDeliveriesResponse\x12N\n" +
+	"\rGetCacheStats\x12\x1d.explore.GetCacheStatsRequest\x1a\x1e.explore.GetCacheStatsResponse\x12B\n" +
+	"\tListUsers\x12\x19.explore.ListUsersRequest\x1a\x1a.explore.ListUsersResponseb\x06proto3"
 
 var (
 	file_explore_service_proto_rawDescOnce sync.Once
@@ -413,31 +2925,122 @@ func file_explore_service_proto_rawDescGZIP() []byte {
 	return file_explore_service_proto_rawDescData
 }
 
-var file_explore_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_explore_service_proto_msgTypes = make([]protoimpl.MessageInfo, 49)
 var file_explore_service_proto_goTypes = []any{
-	(*ListLikedYouRequest)(nil),        // 0: explore.ListLikedYouRequest
-	(*ListLikedYouResponse)(nil),       // 1: explore.ListLikedYouResponse
-	(*CountLikedYouRequest)(nil),       // 2: explore.CountLikedYouRequest
-	(*CountLikedYouResponse)(nil),      // 3: explore.CountLikedYouResponse
-	(*PutDecisionRequest)(nil),         // 4: explore.PutDecisionRequest
-	(*PutDecisionResponse)(nil),        // 5: explore.PutDecisionResponse
-	(*ListLikedYouResponse_Liker)(nil), // 6: explore.ListLikedYouResponse.Liker
+	(*ListLikedYouRequest)(nil),                    // 0: explore.ListLikedYouRequest
+	(*ListLikedYouResponse)(nil),                   // 1: explore.ListLikedYouResponse
+	(*ListMatchesRequest)(nil),                     // 2: explore.ListMatchesRequest
+	(*ListMatchesResponse)(nil),                    // 3: explore.ListMatchesResponse
+	(*CountLikedYouRequest)(nil),                   // 4: explore.CountLikedYouRequest
+	(*CountLikedYouResponse)(nil),                  // 5: explore.CountLikedYouResponse
+	(*PutDecisionRequest)(nil),                     // 6: explore.PutDecisionRequest
+	(*PutDecisionResponse)(nil),                    // 7: explore.PutDecisionResponse
+	(*PutDecisionsRequest)(nil),                    // 8: explore.PutDecisionsRequest
+	(*PutDecisionsResponse)(nil),                   // 9: explore.PutDecisionsResponse
+	(*GetQuotaRequest)(nil),                        // 10: explore.GetQuotaRequest
+	(*GetQuotaResponse)(nil),                       // 11: explore.GetQuotaResponse
+	(*BlockUserRequest)(nil),                       // 12: explore.BlockUserRequest
+	(*BlockUserResponse)(nil),                      // 13: explore.BlockUserResponse
+	(*ReportUserRequest)(nil),                      // 14: explore.ReportUserRequest
+	(*ReportUserResponse)(nil),                     // 15: explore.ReportUserResponse
+	(*UnmatchRequest)(nil),                         // 16: explore.UnmatchRequest
+	(*UnmatchResponse)(nil),                        // 17: explore.UnmatchResponse
+	(*ListCandidatesRequest)(nil),                  // 18: explore.ListCandidatesRequest
+	(*ListCandidatesResponse)(nil),                 // 19: explore.ListCandidatesResponse
+	(*WatchRequest)(nil),                           // 20: explore.WatchRequest
+	(*LikeEvent)(nil),                              // 21: explore.LikeEvent
+	(*MatchEvent)(nil),                             // 22: explore.MatchEvent
+	(*ListDesirabilityRequest)(nil),                // 23: explore.ListDesirabilityRequest
+	(*ListDesirabilityResponse)(nil),               // 24: explore.ListDesirabilityResponse
+	(*RecomputeDesirabilityRequest)(nil),           // 25: explore.RecomputeDesirabilityRequest
+	(*RecomputeDesirabilityResponse)(nil),          // 26: explore.RecomputeDesirabilityResponse
+	(*CreateWebhookSubscriptionRequest)(nil),       // 27: explore.CreateWebhookSubscriptionRequest
+	(*WebhookSubscription)(nil),                    // 28: explore.WebhookSubscription
+	(*ListWebhookSubscriptionsRequest)(nil),        // 29: explore.ListWebhookSubscriptionsRequest
+	(*ListWebhookSubscriptionsResponse)(nil),       // 30: explore.ListWebhookSubscriptionsResponse
+	(*DeleteWebhookSubscriptionRequest)(nil),       // 31: explore.DeleteWebhookSubscriptionRequest
+	(*DeleteWebhookSubscriptionResponse)(nil),      // 32: explore.DeleteWebhookSubscriptionResponse
+	(*ListWebhookDeliveriesRequest)(nil),           // 33: explore.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil),          // 34: explore.ListWebhookDeliveriesResponse
+	(*ReplayWebhookDeliveriesRequest)(nil),         // 35: explore.ReplayWebhookDeliveriesRequest
+	(*ReplayWebhookDeliveriesResponse)(nil),        // 36: explore.ReplayWebhookDeliveriesResponse
+	(*GetCacheStatsRequest)(nil),                   // 37: explore.GetCacheStatsRequest
+	(*GetCacheStatsResponse)(nil),                  // 38: explore.GetCacheStatsResponse
+	(*ListUsersRequest)(nil),                       // 39: explore.ListUsersRequest
+	(*ListUsersResponse)(nil),                      // 40: explore.ListUsersResponse
+	(*ListLikedYouResponse_Liker)(nil),             // 41: explore.ListLikedYouResponse.Liker
+	(*CountLikedYouResponse_Breakdown)(nil),        // 42: explore.CountLikedYouResponse.Breakdown
+	(*PutDecisionsResponse_Result)(nil),            // 43: explore.PutDecisionsResponse.Result
+	(*ListCandidatesResponse_Candidate)(nil),       // 44: explore.ListCandidatesResponse.Candidate
+	(*ListDesirabilityResponse_Score)(nil),         // 45: explore.ListDesirabilityResponse.Score
+	(*ListWebhookDeliveriesResponse_Attempt)(nil),  // 46: explore.ListWebhookDeliveriesResponse.Attempt
+	(*ListWebhookDeliveriesResponse_Delivery)(nil), // 47: explore.ListWebhookDeliveriesResponse.Delivery
+	(*ListUsersResponse_User)(nil),                 // 48: explore.ListUsersResponse.User
 }
 var file_explore_service_proto_depIdxs = []int32{
-	6, // 0: explore.ListLikedYouResponse.likers:type_name -> explore.ListLikedYouResponse.Liker
-	0, // 1: explore.ExploreService.ListLikedYou:input_type -> explore.ListLikedYouRequest
-	0, // 2: explore.ExploreService.ListNewLikedYou:input_type -> explore.ListLikedYouRequest
-	2, // 3: explore.ExploreService.CountLikedYou:input_type -> explore.CountLikedYouRequest
-	4, // 4: explore.ExploreService.PutDecision:input_type -> explore.PutDecisionRequest
-	1, // 5: explore.ExploreService.ListLikedYou:output_type -> explore.ListLikedYouResponse
-	1, // 6: explore.ExploreService.ListNewLikedYou:output_type -> explore.ListLikedYouResponse
-	3, // 7: explore.ExploreService.CountLikedYou:output_type -> explore.CountLikedYouResponse
-	5, // 8: explore.ExploreService.PutDecision:output_type -> explore.PutDecisionResponse
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	41, // 0: explore.ListLikedYouResponse.likers:type_name -> explore.ListLikedYouResponse.Liker
+	22, // 1: explore.ListMatchesResponse.matches:type_name -> explore.MatchEvent
+	42, // 2: explore.CountLikedYouResponse.breakdown:type_name -> explore.CountLikedYouResponse.Breakdown
+	6,  // 3: explore.PutDecisionsRequest.decisions:type_name -> explore.PutDecisionRequest
+	43, // 4: explore.PutDecisionsResponse.results:type_name -> explore.PutDecisionsResponse.Result
+	44, // 5: explore.ListCandidatesResponse.candidates:type_name -> explore.ListCandidatesResponse.Candidate
+	41, // 6: explore.LikeEvent.liker:type_name -> explore.ListLikedYouResponse.Liker
+	45, // 7: explore.ListDesirabilityResponse.scores:type_name -> explore.ListDesirabilityResponse.Score
+	28, // 8: explore.ListWebhookSubscriptionsResponse.subscriptions:type_name -> explore.WebhookSubscription
+	47, // 9: explore.ListWebhookDeliveriesResponse.deliveries:type_name -> explore.ListWebhookDeliveriesResponse.Delivery
+	48, // 10: explore.ListUsersResponse.users:type_name -> explore.ListUsersResponse.User
+	46, // 11: explore.ListWebhookDeliveriesResponse.Delivery.attempts:type_name -> explore.ListWebhookDeliveriesResponse.Attempt
+	0,  // 12: explore.ExploreService.ListLikedYou:input_type -> explore.ListLikedYouRequest
+	0,  // 13: explore.ExploreService.ListNewLikedYou:input_type -> explore.ListLikedYouRequest
+	2,  // 14: explore.ExploreService.ListMatches:input_type -> explore.ListMatchesRequest
+	4,  // 15: explore.ExploreService.CountLikedYou:input_type -> explore.CountLikedYouRequest
+	6,  // 16: explore.ExploreService.PutDecision:input_type -> explore.PutDecisionRequest
+	8,  // 17: explore.ExploreService.PutDecisions:input_type -> explore.PutDecisionsRequest
+	10, // 18: explore.ExploreService.GetQuota:input_type -> explore.GetQuotaRequest
+	12, // 19: explore.ExploreService.BlockUser:input_type -> explore.BlockUserRequest
+	12, // 20: explore.ExploreService.UnblockUser:input_type -> explore.BlockUserRequest
+	14, // 21: explore.ExploreService.ReportUser:input_type -> explore.ReportUserRequest
+	16, // 22: explore.ExploreService.Unmatch:input_type -> explore.UnmatchRequest
+	18, // 23: explore.ExploreService.ListCandidates:input_type -> explore.ListCandidatesRequest
+	20, // 24: explore.ExploreService.WatchLikes:input_type -> explore.WatchRequest
+	20, // 25: explore.ExploreService.WatchMatches:input_type -> explore.WatchRequest
+	23, // 26: explore.AdminService.ListDesirability:input_type -> explore.ListDesirabilityRequest
+	25, // 27: explore.AdminService.RecomputeDesirability:input_type -> explore.RecomputeDesirabilityRequest
+	27, // 28: explore.AdminService.CreateWebhookSubscription:input_type -> explore.CreateWebhookSubscriptionRequest
+	29, // 29: explore.AdminService.ListWebhookSubscriptions:input_type -> explore.ListWebhookSubscriptionsRequest
+	31, // 30: explore.AdminService.DeleteWebhookSubscription:input_type -> explore.DeleteWebhookSubscriptionRequest
+	33, // 31: explore.AdminService.ListWebhookDeliveries:input_type -> explore.ListWebhookDeliveriesRequest
+	35, // 32: explore.AdminService.ReplayWebhookDeliveries:input_type -> explore.ReplayWebhookDeliveriesRequest
+	37, // 33: explore.AdminService.GetCacheStats:input_type -> explore.GetCacheStatsRequest
+	39, // 34: explore.AdminService.ListUsers:input_type -> explore.ListUsersRequest
+	1,  // 35: explore.ExploreService.ListLikedYou:output_type -> explore.ListLikedYouResponse
+	1,  // 36: explore.ExploreService.ListNewLikedYou:output_type -> explore.ListLikedYouResponse
+	3,  // 37: explore.ExploreService.ListMatches:output_type -> explore.ListMatchesResponse
+	5,  // 38: explore.ExploreService.CountLikedYou:output_type -> explore.CountLikedYouResponse
+	7,  // 39: explore.ExploreService.PutDecision:output_type -> explore.PutDecisionResponse
+	9,  // 40: explore.ExploreService.PutDecisions:output_type -> explore.PutDecisionsResponse
+	11, // 41: explore.ExploreService.GetQuota:output_type -> explore.GetQuotaResponse
+	13, // 42: explore.ExploreService.BlockUser:output_type -> explore.BlockUserResponse
+	13, // 43: explore.ExploreService.UnblockUser:output_type -> explore.BlockUserResponse
+	15, // 44: explore.ExploreService.ReportUser:output_type -> explore.ReportUserResponse
+	17, // 45: explore.ExploreService.Unmatch:output_type -> explore.UnmatchResponse
+	19, // 46: explore.ExploreService.ListCandidates:output_type -> explore.ListCandidatesResponse
+	21, // 47: explore.ExploreService.WatchLikes:output_type -> explore.LikeEvent
+	22, // 48: explore.ExploreService.WatchMatches:output_type -> explore.MatchEvent
+	24, // 49: explore.AdminService.ListDesirability:output_type -> explore.ListDesirabilityResponse
+	26, // 50: explore.AdminService.RecomputeDesirability:output_type -> explore.RecomputeDesirabilityResponse
+	28, // 51: explore.AdminService.CreateWebhookSubscription:output_type -> explore.WebhookSubscription
+	30, // 52: explore.AdminService.ListWebhookSubscriptions:output_type -> explore.ListWebhookSubscriptionsResponse
+	32, // 53: explore.AdminService.DeleteWebhookSubscription:output_type -> explore.DeleteWebhookSubscriptionResponse
+	34, // 54: explore.AdminService.ListWebhookDeliveries:output_type -> explore.ListWebhookDeliveriesResponse
+	36, // 55: explore.AdminService.ReplayWebhookDeliveries:output_type -> explore.ReplayWebhookDeliveriesResponse
+	38, // 56: explore.AdminService.GetCacheStats:output_type -> explore.GetCacheStatsResponse
+	40, // 57: explore.AdminService.ListUsers:output_type -> explore.ListUsersResponse
+	35, // [35:58] is the sub-list for method output_type
+	12, // [12:35] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_explore_service_proto_init() }
@@ -447,15 +3050,27 @@ func file_explore_service_proto_init() {
 	}
 	file_explore_service_proto_msgTypes[0].OneofWrappers = []any{}
 	file_explore_service_proto_msgTypes[1].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[4].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[5].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[6].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[18].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[19].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[23].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[33].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[38].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[39].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[42].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[43].OneofWrappers = []any{}
+	file_explore_service_proto_msgTypes[46].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_explore_service_proto_rawDesc), len(file_explore_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   49,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_explore_service_proto_goTypes,
 		DependencyIndexes: file_explore_service_proto_depIdxs,
@@ -1,6 +1,15 @@
+CREATE TABLE tier_quotas (
+    tier        TEXT PRIMARY KEY,
+    daily_likes INTEGER NOT NULL
+);
+
+INSERT INTO tier_quotas (tier, daily_likes) VALUES ('free', 25), ('premium', 500);
+
 CREATE TABLE users (
     id          UUID PRIMARY KEY    DEFAULT gen_random_uuid(),
-    created_at  TIMESTAMP           DEFAULT NOW()
+    created_at  TIMESTAMP           DEFAULT NOW(),
+    tier        TEXT NOT NULL       DEFAULT 'free' REFERENCES tier_quotas(tier),
+    desirability DOUBLE PRECISION NOT NULL DEFAULT 1000
 );
 
 CREATE TABLE decisions (
@@ -9,5 +18,116 @@ CREATE TABLE decisions (
     from_user   UUID NOT NULL       REFERENCES users(id),
     to_user     UUID NOT NULL       REFERENCES users(id),
     liked       BOOLEAN NOT NULL,
+    decided_at  TIMESTAMP           DEFAULT NOW(),
+    desirability_delta DOUBLE PRECISION NOT NULL DEFAULT 0,
     UNIQUE(from_user, to_user)
 );
+
+CREATE TABLE rate_limits (
+    key         TEXT PRIMARY KEY,
+    tokens      DOUBLE PRECISION NOT NULL,
+    allowed     BOOLEAN NOT NULL,
+    updated_at  TIMESTAMP           DEFAULT NOW()
+);
+
+CREATE TABLE like_usage (
+    user_id     UUID NOT NULL       REFERENCES users(id),
+    day         DATE NOT NULL,
+    used        INTEGER NOT NULL,
+    PRIMARY KEY(user_id, day)
+);
+
+CREATE TABLE blocks (
+    blocker     UUID NOT NULL       REFERENCES users(id),
+    blocked     UUID NOT NULL       REFERENCES users(id),
+    created_at  TIMESTAMP           DEFAULT NOW(),
+    PRIMARY KEY(blocker, blocked)
+);
+
+CREATE TABLE reports (
+    id          SERIAL PRIMARY KEY,
+    created_at  TIMESTAMP           DEFAULT NOW(),
+    reporter    UUID NOT NULL       REFERENCES users(id),
+    reported    UUID NOT NULL       REFERENCES users(id),
+    reason      TEXT NOT NULL
+);
+
+CREATE TABLE unmatches (
+    id          SERIAL PRIMARY KEY,
+    created_at  TIMESTAMP           DEFAULT NOW(),
+    actor       UUID NOT NULL       REFERENCES users(id),
+    recipient   UUID NOT NULL       REFERENCES users(id)
+);
+
+CREATE TABLE outbox (
+    id               BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMP           DEFAULT NOW(),
+    event_type       TEXT NOT NULL,
+    payload          JSONB NOT NULL,
+    attempts         INTEGER NOT NULL    DEFAULT 0,
+    next_attempt_at  TIMESTAMP NOT NULL  DEFAULT NOW(),
+    last_error       TEXT,
+    delivered_at     TIMESTAMP,
+    dead_lettered_at TIMESTAMP
+);
+
+CREATE TABLE webhook_subscriptions (
+    id           SERIAL PRIMARY KEY,
+    created_at   TIMESTAMP           DEFAULT NOW(),
+    url          TEXT NOT NULL,
+    secret       TEXT NOT NULL,
+    event_types  TEXT[] NOT NULL,
+    active       BOOLEAN NOT NULL    DEFAULT true
+);
+
+CREATE TABLE webhook_deliveries (
+    id               BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMP           DEFAULT NOW(),
+    subscription_id  INTEGER NOT NULL    REFERENCES webhook_subscriptions(id),
+    event_type       TEXT NOT NULL,
+    payload          JSONB NOT NULL,
+    attempts         INTEGER NOT NULL    DEFAULT 0,
+    next_attempt_at  TIMESTAMP NOT NULL  DEFAULT NOW(),
+    last_status_code INTEGER,
+    last_error       TEXT,
+    delivered_at     TIMESTAMP,
+    failed_at        TIMESTAMP
+);
+
+CREATE TABLE webhook_delivery_attempts (
+    id           BIGSERIAL PRIMARY KEY,
+    delivery_id  BIGINT NOT NULL     REFERENCES webhook_deliveries(id),
+    attempted_at TIMESTAMP           DEFAULT NOW(),
+    status_code  INTEGER,
+    error        TEXT,
+    duration_ms  INTEGER NOT NULL
+);
+
+CREATE TABLE idempotency_keys (
+    actor_user_id     UUID NOT NULL,
+    key               TEXT NOT NULL,
+    created_at        TIMESTAMP           DEFAULT NOW(),
+    expires_at        TIMESTAMP NOT NULL,
+    recipient_user_id UUID NOT NULL,
+    liked             BOOLEAN NOT NULL,
+    mutual_likes      BOOLEAN,
+    PRIMARY KEY (actor_user_id, key)
+);
+
+CREATE TABLE like_counts (
+    user_id     UUID PRIMARY KEY    REFERENCES users(id),
+    count       BIGINT NOT NULL     DEFAULT 0
+);
+
+-- indexes for the queries in server/queries, migrations/001_query_indexes.sql adds
+-- these to a database created before they were here
+CREATE INDEX decisions_likes_received_idx ON decisions (to_user, from_user) WHERE liked = true;
+CREATE INDEX decisions_liked_decided_at_idx ON decisions (decided_at) WHERE liked = true;
+CREATE INDEX blocks_blocked_idx ON blocks (blocked, blocker);
+CREATE INDEX unmatches_actor_idx ON unmatches (actor, recipient);
+CREATE INDEX unmatches_recipient_idx ON unmatches (recipient, actor);
+CREATE INDEX unmatches_created_at_idx ON unmatches (created_at);
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (id) WHERE delivered_at IS NULL AND failed_at IS NULL;
+CREATE INDEX webhook_delivery_attempts_delivery_idx ON webhook_delivery_attempts (delivery_id);
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
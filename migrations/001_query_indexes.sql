@@ -0,0 +1,28 @@
+-- Indexes for the queries in server/queries, these are also in init.sql so this is
+-- only needed for a database that was created before they were added:
+--   psql -h localhost -U postgres -d exploredb -f migrations/001_query_indexes.sql
+-- CONCURRENTLY means writes aren't blocked while they build, it can't run in a
+-- transaction so don't run this with --single-transaction
+
+-- likes received, the like count and the likes_received for candidates. from_user
+-- is included as the like list pages by it
+CREATE INDEX CONCURRENTLY IF NOT EXISTS decisions_likes_received_idx ON decisions (to_user, from_user) WHERE liked = true;
+
+-- catching up on events after the listener reconnects, 003 swaps this for decided_at
+CREATE INDEX CONCURRENTLY IF NOT EXISTS decisions_liked_created_at_idx ON decisions (created_at) WHERE liked = true;
+
+-- blocks are always checked in both directions and the primary key only covers one
+CREATE INDEX CONCURRENTLY IF NOT EXISTS blocks_blocked_idx ON blocks (blocked, blocker);
+
+-- unmatches are also checked in both directions
+CREATE INDEX CONCURRENTLY IF NOT EXISTS unmatches_actor_idx ON unmatches (actor, recipient);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS unmatches_recipient_idx ON unmatches (recipient, actor);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS unmatches_created_at_idx ON unmatches (created_at);
+
+-- the relay and dispatcher only ever look for what is still waiting
+CREATE INDEX CONCURRENTLY IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
+CREATE INDEX CONCURRENTLY IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (id) WHERE delivered_at IS NULL AND failed_at IS NULL;
+CREATE INDEX CONCURRENTLY IF NOT EXISTS webhook_delivery_attempts_delivery_idx ON webhook_delivery_attempts (delivery_id);
+
+-- deleting expired keys
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,8 @@
+-- Adds decided_at to decisions, this is also in init.sql so this is only needed for a
+-- database that was created before it was added:
+--   psql -h localhost -U postgres -d exploredb -f migrations/002_decisions_decided_at.sql
+-- Decisions made before this don't know when they were last changed so they start
+-- out as when they were first made
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS decided_at TIMESTAMP;
+UPDATE decisions SET decided_at = created_at WHERE decided_at IS NULL;
+ALTER TABLE decisions ALTER COLUMN decided_at SET DEFAULT NOW();
@@ -0,0 +1,6 @@
+-- Catching up on events now looks for likes by decided_at rather than created_at, this
+-- swaps the index over on a database that was created before that. Run it after 002,
+-- like 001 it can't run in a transaction:
+--   psql -h localhost -U postgres -d exploredb -f migrations/003_events_decided_at_index.sql
+CREATE INDEX CONCURRENTLY IF NOT EXISTS decisions_liked_decided_at_idx ON decisions (decided_at) WHERE liked = true;
+DROP INDEX CONCURRENTLY IF EXISTS decisions_liked_created_at_idx;
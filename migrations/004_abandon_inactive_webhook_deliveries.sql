@@ -0,0 +1,10 @@
+-- Deliveries for a subscription that was deleted before abandon_webhook_deliveries.sql
+-- was added are stuck pending, this marks them failed the same way:
+--   psql -h localhost -U postgres -d exploredb -f migrations/004_abandon_inactive_webhook_deliveries.sql
+UPDATE webhook_deliveries
+SET failed_at = NOW(), last_error = 'subscription was deleted'
+FROM webhook_subscriptions
+WHERE webhook_subscriptions.id = webhook_deliveries.subscription_id
+AND webhook_subscriptions.active = false
+AND webhook_deliveries.delivered_at IS NULL
+AND webhook_deliveries.failed_at IS NULL;
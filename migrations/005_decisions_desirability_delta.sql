@@ -0,0 +1,6 @@
+-- Adds desirability_delta to decisions, this is also in init.sql so this is only needed
+-- for a database that was created before it was added:
+--   psql -h localhost -U postgres -d exploredb -f migrations/005_decisions_desirability_delta.sql
+-- Decisions made before this don't know what they did to the score so they start out
+-- as 0, running recompute-desirability afterwards fills them in
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS desirability_delta DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"net/url"
+	"slices"
+)
+
+// DefaultAdminPort is where the admin service listens if ADMIN_PORT isn't set
+const DefaultAdminPort = "50052"
+
+// AdminServer is for looking into and fixing up the data behind the explore
+// service. There is no auth on any of this so it should never be exposed
+// anywhere the explore service is
+type AdminServer struct {
+	explore.UnimplementedAdminServiceServer
+
+	Port     string
+	Database *pgxpool.Pool
+	Likes    *LikesCache
+}
+
+func (s AdminServer) ListDesirability(ctx context.Context, request *explore.ListDesirabilityRequest) (*explore.ListDesirabilityResponse, error) {
+	log.Printf("ListDesirability request: [limit=%v]", request.GetLimit())
+
+	scores, err := GetDesirabilityScores(ctx, s.Database)
+	if err != nil {
+		log.Printf("error getting desirability scores: %v", err)
+		return nil, err
+	}
+
+	// stats are across every user even if only the top few are returned
+	stats := GetDesirabilityStats(scores)
+
+	if request.Limit != nil && int(*request.Limit) < len(scores) {
+		scores = scores[:*request.Limit]
+	}
+
+	responseScores := make([]*explore.ListDesirabilityResponse_Score, len(scores))
+
+	for i, score := range scores {
+		responseScores[i] = &explore.ListDesirabilityResponse_Score{
+			UserId:         string(score.UserId),
+			Desirability:   score.Desirability,
+			LikesReceived:  score.LikesReceived,
+			PassesReceived: score.PassesReceived,
+		}
+	}
+
+	response := &explore.ListDesirabilityResponse{
+		Scores:            responseScores,
+		Mean:              stats.Mean,
+		StandardDeviation: stats.StandardDeviation,
+		Min:               stats.Min,
+		Max:               stats.Max,
+	}
+
+	return response, nil
+}
+func (s AdminServer) RecomputeDesirability(ctx context.Context, request *explore.RecomputeDesirabilityRequest) (*explore.RecomputeDesirabilityResponse, error) {
+	log.Printf("RecomputeDesirability request")
+
+	result, err := RecomputeDesirability(ctx, s.Database)
+	if err != nil {
+		log.Printf("error recomputing desirability: %v", err)
+		return nil, err
+	}
+
+	response := &explore.RecomputeDesirabilityResponse{
+		Users:     uint64(result.Users),
+		Decisions: uint64(result.Decisions),
+		MaxDrift:  result.MaxDrift,
+	}
+
+	return response, nil
+}
+func (s AdminServer) CreateWebhookSubscription(ctx context.Context, request *explore.CreateWebhookSubscriptionRequest) (*explore.WebhookSubscription, error) {
+	log.Printf("CreateWebhookSubscription request: [url=%v] [events=%v]", request.Url, request.EventTypes)
+
+	parsed, err := url.Parse(request.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid webhook url \"%v\"", request.Url)
+	}
+
+	if request.Secret == "" {
+		return nil, status.Error(codes.InvalidArgument, "webhook secret is required")
+	}
+
+	if len(request.EventTypes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "webhook needs at least one event type")
+	}
+
+	for _, eventType := range request.EventTypes {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown webhook event type \"%v\", must be one of %v", eventType, WebhookEventTypes)
+		}
+	}
+
+	subscription, err := InsertWebhookSubscription(ctx, s.Database, WebhookSubscription{
+		Url:        request.Url,
+		Secret:     request.Secret,
+		EventTypes: request.EventTypes,
+	})
+	if err != nil {
+		log.Printf("error creating webhook subscription: %v", err)
+		return nil, err
+	}
+
+	return webhookSubscriptionToResponse(subscription), nil
+}
+func (s AdminServer) ListWebhookSubscriptions(ctx context.Context, request *explore.ListWebhookSubscriptionsRequest) (*explore.ListWebhookSubscriptionsResponse, error) {
+	log.Printf("ListWebhookSubscriptions request")
+
+	subscriptions, err := GetWebhookSubscriptions(ctx, s.Database)
+	if err != nil {
+		log.Printf("error getting webhook subscriptions: %v", err)
+		return nil, err
+	}
+
+	responseSubscriptions := make([]*explore.WebhookSubscription, len(subscriptions))
+
+	for i, subscription := range subscriptions {
+		responseSubscriptions[i] = webhookSubscriptionToResponse(subscription)
+	}
+
+	response := &explore.ListWebhookSubscriptionsResponse{Subscriptions: responseSubscriptions}
+
+	return response, nil
+}
+func (s AdminServer) DeleteWebhookSubscription(ctx context.Context, request *explore.DeleteWebhookSubscriptionRequest) (*explore.DeleteWebhookSubscriptionResponse, error) {
+	log.Printf("DeleteWebhookSubscription request: [id=%v]", request.Id)
+
+	deleted, err := DeleteWebhookSubscription(ctx, s.Database, int(request.Id))
+	if err != nil {
+		log.Printf("error deleting webhook subscription: %v", err)
+		return nil, err
+	}
+
+	response := &explore.DeleteWebhookSubscriptionResponse{Deleted: deleted}
+
+	return response, nil
+}
+func (s AdminServer) ListWebhookDeliveries(ctx context.Context, request *explore.ListWebhookDeliveriesRequest) (*explore.ListWebhookDeliveriesResponse, error) {
+	log.Printf("ListWebhookDeliveries request: [failed=%v] [limit=%v]", request.FailedOnly, request.GetLimit())
+
+	limit := 50
+	if request.Limit != nil {
+		limit = int(*request.Limit)
+	}
+
+	deliveries, err := GetWebhookDeliveries(ctx, s.Database, request.FailedOnly, limit)
+	if err != nil {
+		log.Printf("error getting webhook deliveries: %v", err)
+		return nil, err
+	}
+
+	responseDeliveries := make([]*explore.ListWebhookDeliveriesResponse_Delivery, len(deliveries))
+
+	for i, delivery := range deliveries {
+		attempts := make([]*explore.ListWebhookDeliveriesResponse_Attempt, len(delivery.AttemptLog))
+
+		for j, attempt := range delivery.AttemptLog {
+			attempts[j] = &explore.ListWebhookDeliveriesResponse_Attempt{
+				UnixTimestamp: uint64(attempt.AttemptedAt.Unix()),
+				Error:         attempt.Error,
+				DurationMs:    uint64(attempt.DurationMs),
+			}
+
+			if attempt.StatusCode != nil {
+				statusCode := uint32(*attempt.StatusCode)
+				attempts[j].StatusCode = &statusCode
+			}
+		}
+
+		responseDeliveries[i] = &explore.ListWebhookDeliveriesResponse_Delivery{
+			Id:             uint64(delivery.Id),
+			SubscriptionId: uint64(delivery.SubscriptionId),
+			EventType:      delivery.EventType,
+			UnixTimestamp:  uint64(delivery.CreatedAt.Unix()),
+			Delivered:      delivery.DeliveredAt != nil,
+			Failed:         delivery.FailedAt != nil,
+			Attempts:       attempts,
+		}
+	}
+
+	response := &explore.ListWebhookDeliveriesResponse{Deliveries: responseDeliveries}
+
+	return response, nil
+}
+func (s AdminServer) ReplayWebhookDeliveries(ctx context.Context, request *explore.ReplayWebhookDeliveriesRequest) (*explore.ReplayWebhookDeliveriesResponse, error) {
+	log.Printf("ReplayWebhookDeliveries request: [ids=%v] [all=%v]", request.DeliveryIds, request.AllFailed)
+
+	ids := make([]int64, len(request.DeliveryIds))
+	for i, id := range request.DeliveryIds {
+		ids[i] = int64(id)
+	}
+
+	replayed, err := ReplayWebhookDeliveries(ctx, s.Database, ids, request.AllFailed)
+	if err != nil {
+		log.Printf("error replaying webhook deliveries: %v", err)
+		return nil, err
+	}
+
+	response := &explore.ReplayWebhookDeliveriesResponse{Replayed: uint64(replayed)}
+
+	return response, nil
+}
+func (s AdminServer) GetCacheStats(ctx context.Context, request *explore.GetCacheStatsRequest) (*explore.GetCacheStatsResponse, error) {
+	log.Printf("GetCacheStats request")
+
+	stats := s.Likes.Stats()
+
+	response := &explore.GetCacheStatsResponse{
+		Hits:    stats.Hits,
+		Misses:  stats.Misses,
+		HitRate: stats.HitRate(),
+	}
+
+	switch backend := s.Likes.Backend.(type) {
+	case *MemoryCache:
+		entries := uint64(backend.Len())
+
+		response.Backend = "memory"
+		response.Entries = &entries
+	case *RedisCache:
+		response.Backend = "redis"
+	case NoCache:
+		response.Backend = "none"
+	}
+
+	return response, nil
+}
+func (s AdminServer) ListUsers(ctx context.Context, request *explore.ListUsersRequest) (*explore.ListUsersResponse, error) {
+	log.Printf("ListUsers request: [tier=%v]", request.GetTier())
+
+	if request.Tier != nil && *request.Tier != "free" && *request.Tier != "premium" {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown tier \"%v\"", *request.Tier)
+	}
+
+	users, err := GetAllUsers(ctx, s.Database)
+	if err != nil {
+		log.Printf("error getting users: %v", err)
+		return nil, err
+	}
+
+	responseUsers := make([]*explore.ListUsersResponse_User, 0, len(users))
+
+	for _, user := range users {
+		if request.Tier != nil && user.Tier != *request.Tier {
+			continue
+		}
+
+		responseUsers = append(responseUsers, &explore.ListUsersResponse_User{
+			UserId:        string(user.Id),
+			Tier:          user.Tier,
+			UnixTimestamp: uint64(user.CreatedAt.Unix()),
+			Desirability:  user.Desirability,
+		})
+	}
+
+	response := &explore.ListUsersResponse{Users: responseUsers}
+
+	return response, nil
+}
+
+func webhookSubscriptionToResponse(subscription WebhookSubscription) *explore.WebhookSubscription {
+	return &explore.WebhookSubscription{
+		Id:            uint64(subscription.Id),
+		Url:           subscription.Url,
+		EventTypes:    subscription.EventTypes,
+		UnixTimestamp: uint64(subscription.CreatedAt.Unix()),
+	}
+}
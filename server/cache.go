@@ -0,0 +1,326 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache is somewhere to keep the result of a query for a while. Values are
+// always already encoded so any backend that can store bytes works
+type Cache interface {
+	// Get returns false if the key isn't there or has expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+const (
+	DefaultCacheSize = 10000
+	DefaultCacheTTL  = 30 * time.Second
+)
+
+func NewCache(backend string, address string, size int) (Cache, error) {
+	switch backend {
+	case "", "memory":
+		return NewMemoryCache(size), nil
+	case "redis":
+		if address == "" {
+			return nil, fmt.Errorf("redis cache needs an address")
+		}
+
+		return NewRedisCache(address), nil
+	case "none":
+		return NoCache{}, nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend \"%v\"", backend)
+	}
+}
+
+type cacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is an LRU cache in this process, once it holds Size entries the
+// least recently used one is thrown away to make room. Every server has its own
+// so a change on one server only clears the cache on that server, the TTL is
+// what stops the others from being wrong forever
+type MemoryCache struct {
+	Size int
+
+	mutex   sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		Size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*cacheEntry)
+
+	if time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		entry.value = value
+		entry.expiresAt = time.Now().Add(ttl)
+
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:       key,
+		value:     value,
+		expiresAt: time.Now().Add(ttl),
+	})
+
+	for c.order.Len() > c.Size {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *MemoryCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
+
+// RedisCache keeps everything in anything that speaks the redis protocol, so
+// every server shares it and a change on one clears it for all of them. Redis
+// handles the expiry and eviction, the size only applies to the memory cache
+type RedisCache struct {
+	Client *redis.Client
+}
+
+func NewRedisCache(address string) *RedisCache {
+	return &RedisCache{Client: redis.NewClient(&redis.Options{Addr: address})}
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.Client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.Client.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	return c.Client.Del(ctx, keys...).Err()
+}
+
+// NoCache never holds anything, every read goes to the database
+type NoCache struct{}
+
+func (c NoCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+func (c NoCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+func (c NoCache) Delete(ctx context.Context, keys ...string) error {
+	return nil
+}
+
+// LikesCache sits in front of the queries ListLikedYou and CountLikedYou make the
+// most, the first page of likes and the like count. Anything that changes what a
+// user has been liked by has to call Invalidate for them once it has committed.
+// If the cache itself fails the query is just run against the database
+type LikesCache struct {
+	Backend Cache
+	TTL     time.Duration
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+type cachedLikesPage struct {
+	Users           []User `json:"users"`
+	PaginationToken string `json:"pagination_token"`
+}
+
+func NewLikesCache(backend Cache, ttl time.Duration) *LikesCache {
+	return &LikesCache{Backend: backend, TTL: ttl}
+}
+
+func likesStartKey(user User) string {
+	return fmt.Sprintf("likes:start:%v", user.Id)
+}
+
+func likeCountKey(user User) string {
+	return fmt.Sprintf("likes:count:%v", user.Id)
+}
+
+// GetAllLikesStart is the same as the GetAllLikesStart query but read through the cache
+func (c *LikesCache) GetAllLikesStart(ctx context.Context, db DBTX, user User) ([]User, string, error) {
+	var page cachedLikesPage
+
+	if c.get(ctx, likesStartKey(user), &page) {
+		return page.Users, page.PaginationToken, nil
+	}
+
+	users, paginationToken, err := GetAllLikesStart(ctx, db, user)
+	if err != nil {
+		return nil, "", err
+	}
+
+	c.set(ctx, likesStartKey(user), cachedLikesPage{Users: users, PaginationToken: paginationToken})
+
+	return users, paginationToken, nil
+}
+
+// GetLikeCount is the same as the GetLikeCount query but read through the cache
+func (c *LikesCache) GetLikeCount(ctx context.Context, db DBTX, user User) (uint64, error) {
+	var count uint64
+
+	if c.get(ctx, likeCountKey(user), &count) {
+		return count, nil
+	}
+
+	count, err := GetLikeCount(ctx, db, user)
+	if err != nil {
+		return 0, err
+	}
+
+	c.set(ctx, likeCountKey(user), count)
+
+	return count, nil
+}
+
+// Invalidate clears everything cached for the users. A read that started before
+// the change committed can still put the old result back, the TTL is what stops
+// that lasting long
+func (c *LikesCache) Invalidate(ctx context.Context, users ...User) {
+	keys := make([]string, 0, len(users)*2)
+
+	for _, user := range users {
+		keys = append(keys, likesStartKey(user), likeCountKey(user))
+	}
+
+	err := c.Backend.Delete(ctx, keys...)
+	if err != nil {
+		log.Printf("error invalidating cache: %v", err)
+	}
+}
+
+func (c *LikesCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+func (c *LikesCache) get(ctx context.Context, key string, out any) bool {
+	value, ok, err := c.Backend.Get(ctx, key)
+	if err != nil {
+		log.Printf("error reading cache [key=%v]: %v", key, err)
+	}
+
+	if !ok || err != nil {
+		c.misses.Add(1)
+		return false
+	}
+
+	err = json.Unmarshal(value, out)
+	if err != nil {
+		log.Printf("error decoding cache [key=%v]: %v", key, err)
+		c.misses.Add(1)
+		return false
+	}
+
+	c.hits.Add(1)
+	return true
+}
+
+func (c *LikesCache) set(ctx context.Context, key string, value any) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("error encoding cache [key=%v]: %v", key, err)
+		return
+	}
+
+	err = c.Backend.Set(ctx, key, encoded, c.TTL)
+	if err != nil {
+		log.Printf("error writing cache [key=%v]: %v", key, err)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheEviction(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		sets    []string
+		gets    []string
+		kept    []string
+		evicted []string
+	}{
+		{name: "under size", size: 3, sets: []string{"a", "b"}, kept: []string{"a", "b"}},
+		{name: "oldest goes first", size: 2, sets: []string{"a", "b", "c"}, kept: []string{"b", "c"}, evicted: []string{"a"}},
+		{name: "reading keeps it", size: 2, sets: []string{"a", "b"}, gets: []string{"a"}, kept: []string{"a"}, evicted: []string{"b"}},
+		{name: "setting again keeps it", size: 2, sets: []string{"a", "b", "a"}, kept: []string{"a", "b"}},
+		{name: "size of one", size: 1, sets: []string{"a", "b", "c"}, kept: []string{"c"}, evicted: []string{"a", "b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := NewMemoryCache(test.size)
+
+			for _, key := range test.sets {
+				_ = cache.Set(ctx, key, []byte(key), time.Minute)
+			}
+
+			for _, key := range test.gets {
+				_, _, _ = cache.Get(ctx, key)
+			}
+
+			// one more set to push out whatever is least recently used
+			if len(test.gets) > 0 {
+				_ = cache.Set(ctx, "new", []byte("new"), time.Minute)
+			}
+
+			for _, key := range test.kept {
+				value, ok, _ := cache.Get(ctx, key)
+				if !ok || string(value) != key {
+					t.Errorf("Get(%v) = %s, %v, want it kept", key, value, ok)
+				}
+			}
+
+			for _, key := range test.evicted {
+				if _, ok, _ := cache.Get(ctx, key); ok {
+					t.Errorf("Get(%v) found it, want it evicted", key)
+				}
+			}
+
+			if cache.Len() > test.size {
+				t.Errorf("Len() = %v, more than the size %v", cache.Len(), test.size)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(10)
+
+	_ = cache.Set(ctx, "short", []byte("short"), time.Millisecond)
+	_ = cache.Set(ctx, "long", []byte("long"), time.Minute)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok, _ := cache.Get(ctx, "short"); ok {
+		t.Errorf("expired entry was returned")
+	}
+
+	if _, ok, _ := cache.Get(ctx, "long"); !ok {
+		t.Errorf("entry that hasn't expired was not returned")
+	}
+
+	if cache.Len() != 1 {
+		t.Errorf("Len() = %v, want the expired entry removed once read", cache.Len())
+	}
+
+	// setting again starts the ttl over
+	_ = cache.Set(ctx, "short", []byte("again"), time.Minute)
+
+	if value, ok, _ := cache.Get(ctx, "short"); !ok || string(value) != "again" {
+		t.Errorf("Get(short) = %s, %v, want the new value", value, ok)
+	}
+}
+
+func TestCacheBackends(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	backends := []struct {
+		name  string
+		cache Cache
+		// advance moves time forward for the backend
+		advance func(d time.Duration)
+	}{
+		{name: "memory", cache: NewMemoryCache(10), advance: func(d time.Duration) { time.Sleep(d) }},
+		{name: "redis", cache: NewRedisCache(server.Addr()), advance: server.FastForward},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := backend.cache
+
+			if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+				t.Fatalf("Get(missing) = %v, %v, want a miss", ok, err)
+			}
+
+			if err := cache.Set(ctx, "a", []byte("1"), time.Minute); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			if err := cache.Set(ctx, "b", []byte("2"), 10*time.Millisecond); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			if value, ok, err := cache.Get(ctx, "a"); !ok || err != nil || string(value) != "1" {
+				t.Fatalf("Get(a) = %s, %v, %v, want 1", value, ok, err)
+			}
+
+			backend.advance(20 * time.Millisecond)
+
+			if _, ok, _ := cache.Get(ctx, "b"); ok {
+				t.Errorf("Get(b) found it after the ttl")
+			}
+
+			if err := cache.Delete(ctx, "a", "missing"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+
+			if _, ok, _ := cache.Get(ctx, "a"); ok {
+				t.Errorf("Get(a) found it after it was deleted")
+			}
+		})
+	}
+}
+
+func TestLikesCacheInvalidate(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	backends := []struct {
+		name  string
+		cache Cache
+	}{
+		{name: "memory", cache: NewMemoryCache(10)},
+		{name: "redis", cache: NewRedisCache(server.Addr())},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			ctx := context.Background()
+			likes := NewLikesCache(backend.cache, time.Minute)
+
+			changed := User{Id: "changed"}
+			other := User{Id: "other"}
+
+			for _, user := range []User{changed, other} {
+				likes.set(ctx, likeCountKey(user), uint64(3))
+				likes.set(ctx, likesStartKey(user), cachedLikesPage{Users: []User{{Id: "liker"}}, PaginationToken: "liker"})
+			}
+
+			// nothing should touch the database while it is cached so there isn't one
+			count, err := likes.GetLikeCount(ctx, nil, changed)
+			if err != nil || count != 3 {
+				t.Fatalf("GetLikeCount() = %v, %v, want the cached 3", count, err)
+			}
+
+			users, token, err := likes.GetAllLikesStart(ctx, nil, changed)
+			if err != nil || len(users) != 1 || token != "liker" {
+				t.Fatalf("GetAllLikesStart() = %v, %v, %v, want the cached page", users, token, err)
+			}
+
+			likes.Invalidate(ctx, changed)
+
+			for _, key := range []string{likeCountKey(changed), likesStartKey(changed)} {
+				if _, ok, _ := backend.cache.Get(ctx, key); ok {
+					t.Errorf("%v is still cached after Invalidate", key)
+				}
+			}
+
+			for _, key := range []string{likeCountKey(other), likesStartKey(other)} {
+				if _, ok, _ := backend.cache.Get(ctx, key); !ok {
+					t.Errorf("%v was cleared but the user didn't change", key)
+				}
+			}
+
+			if stats := likes.Stats(); stats.Hits != 2 || stats.Misses != 0 {
+				t.Errorf("Stats() = %+v, want 2 hits and no misses", stats)
+			}
+		})
+	}
+}
+
+func TestCacheStatsHitRate(t *testing.T) {
+	tests := []struct {
+		stats CacheStats
+		rate  float64
+	}{
+		{stats: CacheStats{}, rate: 0},
+		{stats: CacheStats{Hits: 3, Misses: 1}, rate: 0.75},
+		{stats: CacheStats{Hits: 0, Misses: 5}, rate: 0},
+		{stats: CacheStats{Hits: 5, Misses: 0}, rate: 1},
+	}
+
+	for _, test := range tests {
+		if rate := test.stats.HitRate(); rate != test.rate {
+			t.Errorf("%+v.HitRate() = %v, want %v", test.stats, rate, test.rate)
+		}
+	}
+}
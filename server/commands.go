@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// RunCommand runs one of the admin commands instead of the server, these are
+// for fixing up data in the database without generating anything new
+//
+//	./bin/server recompute-desirability
+//	./bin/server reconcile-like-counts
+//	./bin/server explain [--force-index] [--verbose] [query name...]
+//	./bin/server webhook-receiver <address> <secret> [fail rate]
+func RunCommand(ctx context.Context, db DBTX, name string, args []string) error {
+	switch name {
+	case "webhook-receiver":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: webhook-receiver <address> <secret> [fail rate]")
+		}
+
+		receiver := WebhookReceiver{Secret: args[1]}
+
+		if len(args) > 2 {
+			failRate, err := strconv.ParseFloat(args[2], 64)
+			if err != nil {
+				return fmt.Errorf("invalid fail rate \"%v\": %v", args[2], err)
+			}
+
+			receiver.FailRate = failRate
+		}
+
+		log.Printf("receiving webhooks on %v", args[0])
+
+		return http.ListenAndServe(args[0], receiver)
+	case "recompute-desirability":
+		result, err := RecomputeDesirability(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("recomputed %v users from %v decisions, largest drift was %.2f\n", result.Users, result.Decisions, result.MaxDrift)
+
+		return nil
+	case "reconcile-like-counts":
+		result, err := ReconcileLikeCounts(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("recounted likes for %v users, %v had drifted by %v in total and %v at most\n", result.Users, result.Drifted, result.TotalDrift, result.MaxDrift)
+
+		return nil
+	case "explain":
+		forceIndex := false
+		verbose := false
+
+		var names []string
+
+		for _, arg := range args {
+			switch arg {
+			case "--force-index":
+				forceIndex = true
+			case "--verbose":
+				verbose = true
+			default:
+				names = append(names, arg)
+			}
+		}
+
+		results, err := Explain(ctx, db, names, forceIndex)
+		if err != nil {
+			return err
+		}
+
+		flagged := 0
+
+		for _, result := range results {
+			fmt.Println(result)
+
+			if len(result.SeqScans) > 0 {
+				flagged += 1
+			}
+
+			if verbose {
+				for _, line := range result.Plan {
+					fmt.Printf("    %v\n", line)
+				}
+			}
+		}
+
+		fmt.Printf("explained %v queries, %v used a sequential scan\n", len(results), flagged)
+
+		return nil
+	default:
+		return fmt.Errorf("unknown command \"%v\"", name)
+	}
+}
@@ -6,12 +6,86 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"log"
 	"time"
 )
 
 type UUID string
 
+// DBTX is anything queries can be run on, *pgx.Conn, *pgxpool.Pool and pgx.Tx work so
+// the same functions can be used inside or outside of a transaction. Calling
+// Begin on a pgx.Tx starts a savepoint so functions that need their own
+// transaction still work when called as part of a bigger one
+type DBTX interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Tx is a transaction that can hold on to things that should only happen once
+// it has committed, like telling watchers about a change. Savepoints started
+// from it are a Tx as well, if one rolls back what it held is dropped and if it
+// commits it is handed up to wait on the outer transaction
+type Tx struct {
+	pgx.Tx
+
+	parent      *Tx
+	afterCommit []func()
+}
+
+// BeginTx starts a Tx, if db is already a Tx this is a savepoint in it
+func BeginTx(ctx context.Context, db DBTX) (*Tx, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx, ok := tx.(*Tx); ok {
+		return tx, nil
+	}
+
+	return &Tx{Tx: tx}, nil
+}
+
+func (t *Tx) Begin(ctx context.Context) (pgx.Tx, error) {
+	savepoint, err := t.Tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Tx: savepoint, parent: t}, nil
+}
+
+func (t *Tx) Commit(ctx context.Context) error {
+	err := t.Tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	afterCommit := t.afterCommit
+	t.afterCommit = nil
+
+	if t.parent != nil {
+		t.parent.afterCommit = append(t.parent.afterCommit, afterCommit...)
+		return nil
+	}
+
+	for _, f := range afterCommit {
+		f()
+	}
+
+	return nil
+}
+
+// AfterCommit runs f once the whole transaction has committed, it is never run
+// if it rolls back
+func (t *Tx) AfterCommit(f func()) {
+	t.afterCommit = append(t.afterCommit, f)
+}
+
 //go:embed queries/insert_user.sql
 var insertUserSQL string
 
@@ -33,9 +107,69 @@ var getAllLikesSentSQL string
 //go:embed queries/get_liked_count.sql
 var getLikedCountSQL string
 
+//go:embed queries/get_liked_count_breakdown.sql
+var getLikedCountBreakdownSQL string
+
+//go:embed queries/get_matches.sql
+var getMatchesSQL string
+
 //go:embed queries/get_decision.sql
 var getDecisionSQL string
 
+//go:embed queries/insert_block.sql
+var insertBlockSQL string
+
+//go:embed queries/delete_block.sql
+var deleteBlockSQL string
+
+//go:embed queries/get_block_exists.sql
+var getBlockExistsSQL string
+
+//go:embed queries/dissolve_match.sql
+var dissolveMatchSQL string
+
+//go:embed queries/insert_report.sql
+var insertReportSQL string
+
+//go:embed queries/insert_unmatch.sql
+var insertUnmatchSQL string
+
+//go:embed queries/get_candidates.sql
+var getCandidatesSQL string
+
+//go:embed queries/get_existing_decision.sql
+var getExistingDecisionSQL string
+
+//go:embed queries/update_desirability.sql
+var updateDesirabilitySQL string
+
+//go:embed queries/get_all_decisions.sql
+var getAllDecisionsSQL string
+
+//go:embed queries/get_all_users.sql
+var getAllUsersSQL string
+
+//go:embed queries/set_desirability.sql
+var setDesirabilitySQL string
+
+//go:embed queries/set_decision_desirability_delta.sql
+var setDecisionDesirabilityDeltaSQL string
+
+//go:embed queries/get_desirability_scores.sql
+var getDesirabilityScoresSQL string
+
+//go:embed queries/get_user.sql
+var getUserSQL string
+
+//go:embed queries/set_user_tier.sql
+var setUserTierSQL string
+
+//go:embed queries/get_quota.sql
+var getQuotaSQL string
+
+//go:embed queries/take_like_quota.sql
+var takeLikeQuotaSQL string
+
 const PaginationSize int = 10
 
 func ConnectToDB(ctx context.Context, host string, user string, password string, database string, port string) (*pgx.Conn, error) {
@@ -64,9 +198,43 @@ func ConnectToDB(ctx context.Context, host string, user string, password string,
 	return nil, errors.New("failed to many connection attempts")
 }
 
+// ConnectToPool opens the pool every request shares. A single *pgx.Conn can only
+// run one query at a time so requests handled at the same time would step on
+// each other, the pool gives each of them their own connection. The pool is lazy
+// so it is pinged to wait for the database the same way as ConnectToDB
+func ConnectToPool(ctx context.Context, host string, user string, password string, database string, port string) (*pgxpool.Pool, error) {
+	url := fmt.Sprintf("postgres://%v:%v@%v:%v/%v?sslmode=disable", user, password, host, port, database)
+
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	retryCount := 15
+	retryDelay := 1 * time.Second
+
+	for i := range retryCount {
+		err := pool.Ping(ctx)
+		if err != nil {
+			log.Printf("[%v] failed to connect to database, will retry: %v\n", i, err.Error())
+
+			time.Sleep(retryDelay)
+			continue
+		}
+
+		return pool, nil
+	}
+
+	pool.Close()
+
+	return nil, errors.New("failed to many connection attempts")
+}
+
 type User struct {
-	Id        UUID
-	CreatedAt time.Time
+	Id           UUID
+	CreatedAt    time.Time
+	Tier         string
+	Desirability float64
 }
 
 type Decision struct {
@@ -75,9 +243,33 @@ type Decision struct {
 	FromUser  UUID
 	ToUser    UUID
 	Liked     bool
+
+	// DesirabilityDelta is how much the decision currently adds to the recipients
+	// score, it is only read by GetExistingDecision
+	DesirabilityDelta float64
+}
+
+type Report struct {
+	Id        int
+	CreatedAt time.Time
+	Reporter  UUID
+	Reported  UUID
+	Reason    string
 }
 
-func InsertUser(ctx context.Context, db *pgx.Conn) (User, error) {
+type Unmatch struct {
+	Id        int
+	CreatedAt time.Time
+	Actor     UUID
+	Recipient UUID
+}
+
+type Match struct {
+	UserId    UUID
+	MatchedAt time.Time
+}
+
+func InsertUser(ctx context.Context, db DBTX) (User, error) {
 	var user User
 
 	err := db.QueryRow(ctx, insertUserSQL).Scan(&user.Id, &user.CreatedAt)
@@ -88,7 +280,28 @@ func InsertUser(ctx context.Context, db *pgx.Conn) (User, error) {
 	return user, nil
 }
 
-func InsertDecision(ctx context.Context, db *pgx.Conn, decision Decision) (Decision, error) {
+func GetUser(ctx context.Context, db DBTX, user User, out *User) (bool, error) {
+	err := db.
+		QueryRow(ctx, getUserSQL, user.Id).
+		Scan(&out.Id, &out.CreatedAt, &out.Tier, &out.Desirability)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func SetUserTier(ctx context.Context, db DBTX, user User, tier string) error {
+	_, err := db.Exec(ctx, setUserTierSQL, user.Id, tier)
+	return err
+}
+
+func InsertDecision(ctx context.Context, db DBTX, decision Decision) (Decision, error) {
 	var newDecision Decision
 
 	err := db.
@@ -101,7 +314,7 @@ func InsertDecision(ctx context.Context, db *pgx.Conn, decision Decision) (Decis
 	return newDecision, nil
 }
 
-func GetAllLikesStart(ctx context.Context, db *pgx.Conn, user User) ([]User, string, error) {
+func GetAllLikesStart(ctx context.Context, db DBTX, user User) ([]User, string, error) {
 	rows, err := db.Query(ctx, getAllLikesReceivedStartSQL, user.Id, PaginationSize)
 	if err != nil {
 		return nil, "", err
@@ -117,7 +330,7 @@ func GetAllLikesStart(ctx context.Context, db *pgx.Conn, user User) ([]User, str
 	return users, paginationToken, nil
 }
 
-func GetAllLikesPaged(ctx context.Context, db *pgx.Conn, user User, paginationToken string) ([]User, string, error) {
+func GetAllLikesPaged(ctx context.Context, db DBTX, user User, paginationToken string) ([]User, string, error) {
 	rows, err := db.Query(ctx, getAllLikesReceivedPagedSQL, user.Id, paginationToken, PaginationSize)
 	if err != nil {
 		return nil, "", err
@@ -133,7 +346,43 @@ func GetAllLikesPaged(ctx context.Context, db *pgx.Conn, user User, paginationTo
 	return users, newPaginationToken, nil
 }
 
-func GetAllLikesOneWay(ctx context.Context, db *pgx.Conn, user User) ([]User, error) {
+// GetCandidates gets up to limit candidates in the order the ranker puts them,
+// starting after the given candidate if there is one
+func GetCandidates(ctx context.Context, db DBTX, user User, ranker Ranker, showLikedYou bool, after *RankedCandidate, limit int) ([]RankedCandidate, error) {
+	var afterScore *float64
+	var afterId *UUID
+
+	if after != nil {
+		afterScore = &after.Score
+		afterId = &after.Id
+	}
+
+	rows, err := db.Query(ctx, CandidatesSQL(ranker), user.Id, showLikedYou, afterScore, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var candidates []RankedCandidate
+
+	for rows.Next() {
+		var c RankedCandidate
+		if err := rows.Scan(&c.Id, &c.CreatedAt, &c.Score); err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+func GetAllLikesOneWay(ctx context.Context, db DBTX, user User) ([]User, error) {
 	// I couldn't figure out how to do this query how (I think) it is intended
 	// where its all done in the query I could only get as far as this where I
 	// check for the like both to and from the user and filter it on the server :[
@@ -190,7 +439,7 @@ func GetAllLikesOneWay(ctx context.Context, db *pgx.Conn, user User) ([]User, er
 	return likesRecivedButNotSent, nil
 }
 
-func GetLikeCount(ctx context.Context, db *pgx.Conn, user User) (uint64, error) {
+func GetLikeCount(ctx context.Context, db DBTX, user User) (uint64, error) {
 	var count uint64
 
 	err := db.QueryRow(ctx, getLikedCountSQL, user.Id).Scan(&count)
@@ -201,7 +450,83 @@ func GetLikeCount(ctx context.Context, db *pgx.Conn, user User) (uint64, error)
 	return count, nil
 }
 
-func GetDecision(ctx context.Context, db *pgx.Conn, decision Decision, out *Decision) (bool, error) {
+// LikeCountBreakdown splits up the likes a user received by what they did
+// about them. Unanswered, Passed and Matches always add up to Total
+type LikeCountBreakdown struct {
+	Total      uint64
+	Unanswered uint64
+	Passed     uint64
+	Matches    uint64
+
+	// Since is the number of likes decided after the since time given, including
+	// passes that were changed to a like after it. This is 0 if no time was given
+	Since uint64
+}
+
+func GetLikeCountBreakdown(ctx context.Context, db DBTX, user User, since *time.Time) (LikeCountBreakdown, error) {
+	var breakdown LikeCountBreakdown
+
+	var sinceUTC *time.Time
+	if since != nil {
+		utc := since.UTC()
+		sinceUTC = &utc
+	}
+
+	err := db.
+		QueryRow(ctx, getLikedCountBreakdownSQL, user.Id, sinceUTC).
+		Scan(&breakdown.Total, &breakdown.Unanswered, &breakdown.Passed, &breakdown.Matches, &breakdown.Since)
+	if err != nil {
+		return LikeCountBreakdown{}, err
+	}
+
+	return breakdown, nil
+}
+
+// GetMatches finds everyone the user has matched with, newest first
+func GetMatches(ctx context.Context, db DBTX, user User) ([]Match, error) {
+	rows, err := db.Query(ctx, getMatchesSQL, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var matches []Match
+
+	for rows.Next() {
+		var match Match
+		if err := rows.Scan(&match.UserId, &match.MatchedAt); err != nil {
+			return nil, err
+		}
+
+		matches = append(matches, match)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// GetExistingDecision is the same as GetDecision but also finds passes
+func GetExistingDecision(ctx context.Context, db DBTX, decision Decision, out *Decision) (bool, error) {
+	err := db.
+		QueryRow(ctx, getExistingDecisionSQL, decision.FromUser, decision.ToUser).
+		Scan(&out.Id, &out.CreatedAt, &out.FromUser, &out.ToUser, &out.Liked, &out.DesirabilityDelta)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func GetDecision(ctx context.Context, db DBTX, decision Decision, out *Decision) (bool, error) {
 	err := db.
 		QueryRow(ctx, getDecisionSQL, decision.FromUser, decision.ToUser).
 		Scan(&out.Id, &out.CreatedAt, &out.FromUser, &out.ToUser, &out.Liked)
@@ -217,6 +542,332 @@ func GetDecision(ctx context.Context, db *pgx.Conn, decision Decision, out *Deci
 	return true, nil
 }
 
+func GetQuota(ctx context.Context, db DBTX, user User, out *Quota) (bool, error) {
+	err := db.
+		QueryRow(ctx, getQuotaSQL, user.Id).
+		Scan(&out.Tier, &out.DailyLikes, &out.LikesUsed)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// TakeLikeQuota uses up one of the users likes for today, if they have none left
+// then false is returned and nothing is changed
+func TakeLikeQuota(ctx context.Context, db DBTX, user User) (bool, error) {
+	var used int
+
+	err := db.QueryRow(ctx, takeLikeQuotaSQL, user.Id).Scan(&used)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// BlockUser records the block in a single transaction. A match between the two
+// users is dissolved the same as if the blocker had unmatched them, so it doesn't
+// come back if they are unblocked. Returns true if there was a match, out is then
+// the unmatch that was recorded for it
+func BlockUser(ctx context.Context, db DBTX, blocker User, blocked User, out *Unmatch) (bool, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	// the likes between them stop being counted, but only if they weren't
+	// already hidden by a block from either side
+	alreadyBlocked, err := IsBlocked(ctx, tx, blocker, blocked)
+	if err != nil {
+		return false, err
+	}
+
+	// this is done before the block so the blockers like is taken off the count
+	// by the unmatch, the pair adjustment below then only has what is left
+	dissolved, err := UnmatchUsers(ctx, tx, blocker, blocked, out)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(ctx, insertBlockSQL, blocker.Id, blocked.Id)
+	if err != nil {
+		return false, err
+	}
+
+	if !alreadyBlocked {
+		err = AdjustPairLikeCounts(ctx, tx, blocker, blocked, -1)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return dissolved, nil
+}
+
+func UnblockUser(ctx context.Context, db DBTX, blocker User, blocked User) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, deleteBlockSQL, blocker.Id, blocked.Id)
+	if err != nil {
+		return err
+	}
+
+	// the likes between them are counted again unless the other user has
+	// blocked them as well
+	stillBlocked, err := IsBlocked(ctx, tx, blocker, blocked)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() > 0 && !stillBlocked {
+		err = AdjustPairLikeCounts(ctx, tx, blocker, blocked, 1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// IsBlocked checks for a block in either direction between the two users
+func IsBlocked(ctx context.Context, db DBTX, a User, b User) (bool, error) {
+	var blocked bool
+
+	err := db.QueryRow(ctx, getBlockExistsSQL, a.Id, b.Id).Scan(&blocked)
+	if err != nil {
+		return false, err
+	}
+
+	return blocked, nil
+}
+
+func InsertReport(ctx context.Context, db DBTX, report Report) (Report, error) {
+	newReport := report
+
+	err := db.
+		QueryRow(ctx, insertReportSQL, report.Reporter, report.Reported, report.Reason).
+		Scan(&newReport.Id)
+	if err != nil {
+		return Report{}, err
+	}
+
+	return newReport, nil
+}
+
+// UnmatchUsers dissolves the match and records who did it in one transaction.
+// If the users were not matched nothing is changed and false is returned
+func UnmatchUsers(ctx context.Context, db DBTX, actor User, recipient User, out *Unmatch) (bool, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	// the actors like becomes a pass and the recipients like is hidden by the
+	// unmatch, so whichever of them were counted have to come off the counts
+	actorLikeCounted, err := IsLikeCounted(ctx, tx, actor, recipient)
+	if err != nil {
+		return false, err
+	}
+
+	recipientLikeCounted, err := IsLikeCounted(ctx, tx, recipient, actor)
+	if err != nil {
+		return false, err
+	}
+
+	tag, err := tx.Exec(ctx, dissolveMatchSQL, actor.Id, recipient.Id)
+	if err != nil {
+		return false, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	err = tx.
+		QueryRow(ctx, insertUnmatchSQL, actor.Id, recipient.Id).
+		Scan(&out.Id, &out.CreatedAt, &out.Actor, &out.Recipient)
+	if err != nil {
+		return false, err
+	}
+
+	if actorLikeCounted {
+		err = AdjustLikeCount(ctx, tx, recipient, -1)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if recipientLikeCounted {
+		err = AdjustLikeCount(ctx, tx, actor, -1)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// UpdateDesirability adds delta to the users score, this is done as an increment
+// rather than setting the score so two updates at once don't overwrite each other
+func UpdateDesirability(ctx context.Context, db DBTX, user User, delta float64) (float64, error) {
+	var desirability float64
+
+	err := db.QueryRow(ctx, updateDesirabilitySQL, user.Id, delta).Scan(&desirability)
+	if err != nil {
+		return 0, err
+	}
+
+	return desirability, nil
+}
+
+func SetDecisionDesirabilityDelta(ctx context.Context, db DBTX, decision Decision, delta float64) error {
+	_, err := db.Exec(ctx, setDecisionDesirabilityDeltaSQL, decision.FromUser, decision.ToUser, delta)
+	return err
+}
+
+func GetAllUsers(ctx context.Context, db DBTX) ([]User, error) {
+	rows, err := db.Query(ctx, getAllUsersSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var users []User
+
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.Id, &u.CreatedAt, &u.Tier, &u.Desirability); err != nil {
+			return nil, err
+		}
+
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetAllDecisions gets every decision in the order they were first made
+func GetAllDecisions(ctx context.Context, db DBTX) ([]Decision, error) {
+	rows, err := db.Query(ctx, getAllDecisionsSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var decisions []Decision
+
+	for rows.Next() {
+		var d Decision
+		if err := rows.Scan(&d.Id, &d.CreatedAt, &d.FromUser, &d.ToUser, &d.Liked); err != nil {
+			return nil, err
+		}
+
+		decisions = append(decisions, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return decisions, nil
+}
+
+// SetDesirabilities overwrites the score of every user in the map, and the delta
+// each of the decisions added to get there, in a single transaction so nothing
+// can read a half updated set of scores
+func SetDesirabilities(ctx context.Context, db DBTX, scores map[UUID]float64, decisions []Decision) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for id, score := range scores {
+		batch.Queue(setDesirabilitySQL, id, score)
+	}
+
+	for _, decision := range decisions {
+		batch.Queue(setDecisionDesirabilityDeltaSQL, decision.FromUser, decision.ToUser, decision.DesirabilityDelta)
+	}
+
+	err = tx.SendBatch(ctx, batch).Close()
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+type DesirabilityScore struct {
+	UserId         UUID
+	Desirability   float64
+	LikesReceived  uint64
+	PassesReceived uint64
+}
+
+func GetDesirabilityScores(ctx context.Context, db DBTX) ([]DesirabilityScore, error) {
+	rows, err := db.Query(ctx, getDesirabilityScoresSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var scores []DesirabilityScore
+
+	for rows.Next() {
+		var d DesirabilityScore
+		if err := rows.Scan(&d.UserId, &d.Desirability, &d.LikesReceived, &d.PassesReceived); err != nil {
+			return nil, err
+		}
+
+		scores = append(scores, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
 func RowsToUserList(rows pgx.Rows) ([]User, string, error) {
 	var users []User
 	var paginationToken string
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDB stands in for the database so the queries a function makes can be
+// checked without one. Each query is answered by respond if it knows the answer,
+// then from results by its sql, or with rows if it isn't in there, and is
+// remembered in queries
+type fakeDB struct {
+	rows     [][]any
+	results  map[string][][]any
+	respond  func(sql string, args []any) ([][]any, bool)
+	affected map[string]int64
+	err      error
+
+	queries []fakeQuery
+	sql     string
+	args    []any
+
+	commits   int
+	rollbacks int
+}
+
+type fakeQuery struct {
+	sql  string
+	args []any
+}
+
+func (db *fakeDB) run(sql string, args []any) [][]any {
+	db.queries = append(db.queries, fakeQuery{sql: sql, args: args})
+	db.sql, db.args = sql, args
+
+	if db.respond != nil {
+		if rows, ok := db.respond(sql, args); ok {
+			return rows
+		}
+	}
+
+	if rows, ok := db.results[sql]; ok {
+		return rows
+	}
+
+	return db.rows
+}
+
+// ran is every time the query was run, in order
+func (db *fakeDB) ran(sql string) []fakeQuery {
+	var queries []fakeQuery
+	for _, query := range db.queries {
+		if query.sql == sql {
+			queries = append(queries, query)
+		}
+	}
+
+	return queries
+}
+
+func (db *fakeDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	if db.err != nil {
+		return nil, db.err
+	}
+
+	return &fakeTx{db: db}, nil
+}
+
+func (db *fakeDB) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	db.run(sql, arguments)
+	return pgconn.NewCommandTag(fmt.Sprintf("UPDATE %v", db.affected[sql])), db.err
+}
+
+func (db *fakeDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows := db.run(sql, args)
+	if db.err != nil {
+		return nil, db.err
+	}
+
+	return &fakeRows{rows: rows, index: -1}, nil
+}
+
+func (db *fakeDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return &fakeRows{rows: db.run(sql, args), index: -1, err: db.err}
+}
+
+// fakeTx runs everything on the fakeDB it came from, savepoints included
+type fakeTx struct {
+	db   *fakeDB
+	done bool
+}
+
+func (t *fakeTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	return t.db.Begin(ctx)
+}
+
+func (t *fakeTx) Commit(ctx context.Context) error {
+	if t.done {
+		return pgx.ErrTxClosed
+	}
+
+	t.done = true
+	t.db.commits += 1
+
+	return nil
+}
+
+func (t *fakeTx) Rollback(ctx context.Context) error {
+	if t.done {
+		return pgx.ErrTxClosed
+	}
+
+	t.done = true
+	t.db.rollbacks += 1
+
+	return nil
+}
+
+func (t *fakeTx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return t.db.Exec(ctx, sql, arguments...)
+}
+
+func (t *fakeTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return t.db.Query(ctx, sql, args...)
+}
+
+func (t *fakeTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.db.QueryRow(ctx, sql, args...)
+}
+
+func (t *fakeTx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return 0, fmt.Errorf("fakeTx can't copy")
+}
+
+func (t *fakeTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return nil
+}
+
+func (t *fakeTx) LargeObjects() pgx.LargeObjects {
+	return pgx.LargeObjects{}
+}
+
+func (t *fakeTx) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+	return nil, fmt.Errorf("fakeTx can't prepare")
+}
+
+func (t *fakeTx) Conn() *pgx.Conn {
+	return nil
+}
+
+type fakeRows struct {
+	rows  [][]any
+	index int
+	err   error
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return r.err }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return r.rows[r.index], nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	r.index++
+	return r.index < len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	// QueryRow scans without calling Next first
+	if r.index < 0 && !r.Next() {
+		return pgx.ErrNoRows
+	}
+
+	for i, value := range r.rows[r.index] {
+		target := reflect.ValueOf(dest[i]).Elem()
+		target.Set(reflect.ValueOf(value).Convert(target.Type()))
+	}
+
+	return nil
+}
+
+func TestBlockUser(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	blocker := User{Id: "blocker"}
+	blocked := User{Id: "blocked"}
+
+	tests := []struct {
+		name      string
+		matched   bool
+		dissolved bool
+	}{
+		{name: "not matched", matched: false, dissolved: false},
+		{name: "matched", matched: true, dissolved: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{
+				results: map[string][][]any{
+					getBlockExistsSQL: {{false}},
+					getLikeCountedSQL: {{false}},
+					insertUnmatchSQL:  {{1, now, blocker.Id, blocked.Id}},
+				},
+				affected: map[string]int64{},
+			}
+
+			if test.matched {
+				db.affected[dissolveMatchSQL] = 1
+			}
+
+			var unmatch Unmatch
+
+			dissolved, err := BlockUser(context.Background(), db, blocker, blocked, &unmatch)
+			if err != nil {
+				t.Fatalf("BlockUser() error = %v", err)
+			}
+
+			if dissolved != test.dissolved {
+				t.Errorf("BlockUser() = %v, want %v", dissolved, test.dissolved)
+			}
+
+			// the match is dissolved exactly like an unmatch by the blocker
+			dissolves := db.ran(dissolveMatchSQL)
+			if len(dissolves) != 1 || !reflect.DeepEqual(dissolves[0].args, []any{blocker.Id, blocked.Id}) {
+				t.Errorf("dissolve_match ran %v, want once for the blocker", dissolves)
+			}
+
+			if got := len(db.ran(insertUnmatchSQL)); got != boolToInt(test.dissolved) {
+				t.Errorf("insert_unmatch ran %v times, want %v", got, boolToInt(test.dissolved))
+			}
+
+			if test.dissolved && (unmatch.Id != 1 || unmatch.Actor != blocker.Id || unmatch.Recipient != blocked.Id) {
+				t.Errorf("unmatch = %+v, want the one recorded for the block", unmatch)
+			}
+
+			if len(db.ran(insertBlockSQL)) != 1 {
+				t.Errorf("block was not inserted")
+			}
+
+			if db.commits == 0 {
+				t.Errorf("nothing was committed")
+			}
+		})
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func TestGetLikeCountBreakdown(t *testing.T) {
+	since := time.Date(2024, 6, 15, 14, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	sinceUTC := since.UTC()
+
+	tests := []struct {
+		name  string
+		since *time.Time
+		arg   *time.Time
+	}{
+		{name: "no since", since: nil, arg: nil},
+		{name: "since is sent as utc", since: &since, arg: &sinceUTC},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{rows: [][]any{{5, 2, 1, 2, 3}}}
+
+			breakdown, err := GetLikeCountBreakdown(context.Background(), db, User{Id: "a"}, test.since)
+			if err != nil {
+				t.Fatalf("GetLikeCountBreakdown() error = %v", err)
+			}
+
+			want := LikeCountBreakdown{Total: 5, Unanswered: 2, Passed: 1, Matches: 2, Since: 3}
+			if breakdown != want {
+				t.Errorf("GetLikeCountBreakdown() = %+v, want %+v", breakdown, want)
+			}
+
+			if !reflect.DeepEqual(db.args, []any{UUID("a"), test.arg}) {
+				t.Errorf("query args = %v, want %v", db.args, test.arg)
+			}
+
+			// created_at stays as when a pass was first made so a pass turned into a
+			// like would never be new
+			if !strings.Contains(db.sql, "decisions.decided_at > $2") {
+				t.Errorf("since isn't counted from when the like was last decided")
+			}
+		})
+	}
+}
+
+func TestUnmatchUsersLikeCounts(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	actor := User{Id: "actor"}
+	recipient := User{Id: "recipient"}
+
+	tests := []struct {
+		name string
+		// counted is whether the like from the key to the other user is counted
+		counted map[UUID]bool
+		matched bool
+		// adjusted is who has a like taken off their count
+		adjusted []UUID
+	}{
+		{
+			name:     "both likes counted",
+			counted:  map[UUID]bool{actor.Id: true, recipient.Id: true},
+			matched:  true,
+			adjusted: []UUID{recipient.Id, actor.Id},
+		},
+		{
+			name:     "recipients like hidden by an earlier unmatch",
+			counted:  map[UUID]bool{actor.Id: true, recipient.Id: false},
+			matched:  true,
+			adjusted: []UUID{recipient.Id},
+		},
+		{
+			name:     "blocked so neither is counted",
+			counted:  map[UUID]bool{actor.Id: false, recipient.Id: false},
+			matched:  true,
+			adjusted: nil,
+		},
+		{
+			name:     "not matched",
+			counted:  map[UUID]bool{actor.Id: true, recipient.Id: true},
+			matched:  false,
+			adjusted: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{
+				results: map[string][][]any{
+					insertUnmatchSQL: {{1, now, actor.Id, recipient.Id}},
+				},
+				respond: func(sql string, args []any) ([][]any, bool) {
+					if sql != getLikeCountedSQL {
+						return nil, false
+					}
+
+					return [][]any{{test.counted[args[0].(UUID)]}}, true
+				},
+				affected: map[string]int64{},
+			}
+
+			if test.matched {
+				db.affected[dissolveMatchSQL] = 1
+			}
+
+			var unmatch Unmatch
+
+			dissolved, err := UnmatchUsers(context.Background(), db, actor, recipient, &unmatch)
+			if err != nil {
+				t.Fatalf("UnmatchUsers() error = %v", err)
+			}
+
+			if dissolved != test.matched {
+				t.Errorf("UnmatchUsers() = %v, want %v", dissolved, test.matched)
+			}
+
+			var adjusted []UUID
+			for _, query := range db.ran(adjustLikeCountSQL) {
+				if query.args[1] != -1 {
+					t.Errorf("like count adjusted by %v, want -1", query.args[1])
+				}
+
+				adjusted = append(adjusted, query.args[0].(UUID))
+			}
+
+			if !reflect.DeepEqual(adjusted, test.adjusted) {
+				t.Errorf("like counts adjusted for %v, want %v", adjusted, test.adjusted)
+			}
+		})
+	}
+}
+
+func TestLikeQueriesHideUnmatchedLikes(t *testing.T) {
+	// the like lists, the cached count and the recount all have to agree on which
+	// likes an unmatch hides or CountLikedYou and ListNewLikedYou drift apart
+	queries := map[string]string{
+		"get_all_likes_received":       getAllLikesReceivedSQL,
+		"get_all_likes_received_start": getAllLikesReceivedStartSQL,
+		"get_all_likes_received_paged": getAllLikesReceivedPagedSQL,
+		"get_liked_count_breakdown":    getLikedCountBreakdownSQL,
+		"get_like_counted":             getLikeCountedSQL,
+		"adjust_pair_like_counts":      adjustPairLikeCountsSQL,
+		"reconcile_like_counts":        reconcileLikeCountsSQL,
+	}
+
+	for name, sql := range queries {
+		t.Run(name, func(t *testing.T) {
+			if !strings.Contains(sql, "unmatches.created_at >= decisions.decided_at") {
+				t.Errorf("%v doesn't hide only the likes made before an unmatch", name)
+			}
+		})
+	}
+}
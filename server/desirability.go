@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math"
+)
+
+// Desirability is an Elo style score for how much other users like someone.
+// Every decision is treated as a game between the actor and the recipient where
+// a like is a win for the recipient and a pass is a loss. Like Elo the less likely
+// the result was the more it moves the score, so a like from someone with a high
+// score counts for a lot more than a like from someone with a low score
+const (
+	DesirabilityK        float64 = 32
+	DesirabilityBaseline float64 = 1000
+)
+
+// DesirabilityDelta is how much the recipients score changes for a decision made
+// by the actor, only the recipients score changes
+func DesirabilityDelta(recipient float64, actor float64, liked bool) float64 {
+	expected := 1 / (1 + math.Pow(10, (actor-recipient)/400))
+
+	outcome := 0.0
+	if liked {
+		outcome = 1
+	}
+
+	return DesirabilityK * (outcome - expected)
+}
+
+type RecomputeResult struct {
+	Users     int
+	Decisions int
+	MaxDrift  float64
+}
+
+// ReplayDesirability works out the scores of the users from the baseline by making
+// the decisions in order, and sets the delta each decision added along the way.
+// Only the users given are scored so decisions have to be between them
+func ReplayDesirability(users []User, decisions []Decision) map[UUID]float64 {
+	scores := make(map[UUID]float64, len(users))
+	for _, user := range users {
+		scores[user.Id] = DesirabilityBaseline
+	}
+
+	for i, decision := range decisions {
+		delta := DesirabilityDelta(scores[decision.ToUser], scores[decision.FromUser], decision.Liked)
+
+		scores[decision.ToUser] += delta
+		decisions[i].DesirabilityDelta = delta
+	}
+
+	return scores
+}
+
+// SeedDesirability scores newly generated users from the decisions generated
+// between them, nobody else's score is touched
+func SeedDesirability(ctx context.Context, db DBTX, users []User, decisions []Decision) error {
+	scores := ReplayDesirability(users, decisions)
+	return SetDesirabilities(ctx, db, scores, decisions)
+}
+
+// RecomputeDesirability throws away every score and replays all of the decisions
+// in the order they were made. Decisions can be changed after they are made and
+// only the latest version is kept, so the replay won't exactly match the scores
+// built up by PutDecision and the drift between the two is reported back. This
+// overwrites everything so it is only ever run from the admin command or RPC
+func RecomputeDesirability(ctx context.Context, db DBTX) (RecomputeResult, error) {
+	users, err := GetAllUsers(ctx, db)
+	if err != nil {
+		return RecomputeResult{}, err
+	}
+
+	decisions, err := GetAllDecisions(ctx, db)
+	if err != nil {
+		return RecomputeResult{}, err
+	}
+
+	scores := ReplayDesirability(users, decisions)
+
+	maxDrift := 0.0
+	for _, user := range users {
+		maxDrift = math.Max(maxDrift, math.Abs(user.Desirability-scores[user.Id]))
+	}
+
+	err = SetDesirabilities(ctx, db, scores, decisions)
+	if err != nil {
+		return RecomputeResult{}, err
+	}
+
+	result := RecomputeResult{
+		Users:     len(users),
+		Decisions: len(decisions),
+		MaxDrift:  maxDrift,
+	}
+
+	log.Printf("recomputed desirability: [users=%v] [decisions=%v] [drift=%.2f]", result.Users, result.Decisions, result.MaxDrift)
+
+	return result, nil
+}
+
+type DesirabilityStats struct {
+	Mean              float64
+	StandardDeviation float64
+	Min               float64
+	Max               float64
+}
+
+func GetDesirabilityStats(scores []DesirabilityScore) DesirabilityStats {
+	if len(scores) == 0 {
+		return DesirabilityStats{}
+	}
+
+	stats := DesirabilityStats{Min: math.Inf(1), Max: math.Inf(-1)}
+
+	sum := 0.0
+	for _, score := range scores {
+		sum += score.Desirability
+		stats.Min = math.Min(stats.Min, score.Desirability)
+		stats.Max = math.Max(stats.Max, score.Desirability)
+	}
+
+	stats.Mean = sum / float64(len(scores))
+
+	variance := 0.0
+	for _, score := range scores {
+		variance += math.Pow(score.Desirability-stats.Mean, 2)
+	}
+
+	stats.StandardDeviation = math.Sqrt(variance / float64(len(scores)))
+
+	return stats
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDesirabilityDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		recipient float64
+		actor     float64
+		liked     bool
+		delta     float64
+	}{
+		{name: "like between equals", recipient: 1000, actor: 1000, liked: true, delta: 16},
+		{name: "pass between equals", recipient: 1000, actor: 1000, liked: false, delta: -16},
+		{name: "like from someone more desirable", recipient: 1000, actor: 1400, liked: true, delta: 32 * 10.0 / 11},
+		{name: "pass from someone more desirable", recipient: 1000, actor: 1400, liked: false, delta: -32 * 1.0 / 11},
+		{name: "like from someone less desirable", recipient: 1400, actor: 1000, liked: true, delta: 32 * 1.0 / 11},
+		{name: "pass from someone less desirable", recipient: 1400, actor: 1000, liked: false, delta: -32 * 10.0 / 11},
+		{name: "like that was certain", recipient: 5000, actor: 0, liked: true, delta: 0},
+		{name: "pass that was certain", recipient: 0, actor: 5000, liked: false, delta: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			delta := DesirabilityDelta(test.recipient, test.actor, test.liked)
+			if math.Abs(delta-test.delta) > 1e-9 {
+				t.Errorf("DesirabilityDelta(%v, %v, %v) = %v, want %v", test.recipient, test.actor, test.liked, delta, test.delta)
+			}
+
+			// a like and a pass are always K apart and never move the score more than K
+			other := DesirabilityDelta(test.recipient, test.actor, !test.liked)
+			if math.Abs(math.Abs(delta-other)-DesirabilityK) > 1e-9 || math.Abs(delta) > DesirabilityK {
+				t.Errorf("DesirabilityDelta(%v, %v) like and pass = %v and %v, want them %v apart", test.recipient, test.actor, delta, other, DesirabilityK)
+			}
+		})
+	}
+}
+
+func TestGetDesirabilityStats(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		stats  DesirabilityStats
+	}{
+		{name: "no scores", scores: nil, stats: DesirabilityStats{}},
+		{name: "one score", scores: []float64{1000}, stats: DesirabilityStats{Mean: 1000, Min: 1000, Max: 1000}},
+		{name: "spread", scores: []float64{900, 1000, 1100}, stats: DesirabilityStats{Mean: 1000, StandardDeviation: math.Sqrt(20000.0 / 3), Min: 900, Max: 1100}},
+		{name: "all the same", scores: []float64{1016, 1016}, stats: DesirabilityStats{Mean: 1016, Min: 1016, Max: 1016}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var scores []DesirabilityScore
+			for _, score := range test.scores {
+				scores = append(scores, DesirabilityScore{Desirability: score})
+			}
+
+			stats := GetDesirabilityStats(scores)
+			if math.Abs(stats.StandardDeviation-test.stats.StandardDeviation) > 1e-9 {
+				t.Errorf("GetDesirabilityStats() deviation = %v, want %v", stats.StandardDeviation, test.stats.StandardDeviation)
+			}
+
+			stats.StandardDeviation = test.stats.StandardDeviation
+			if stats != test.stats {
+				t.Errorf("GetDesirabilityStats() = %+v, want %+v", stats, test.stats)
+			}
+		})
+	}
+}
+
+func TestReplayDesirability(t *testing.T) {
+	users := []User{{Id: "a"}, {Id: "b"}, {Id: "c"}}
+
+	decisions := []Decision{
+		{FromUser: "a", ToUser: "b", Liked: true},
+		{FromUser: "c", ToUser: "b", Liked: true},
+		{FromUser: "b", ToUser: "a", Liked: false},
+	}
+
+	scores := ReplayDesirability(users, decisions)
+
+	// each decision is scored against where the users are by then, b's second like
+	// is worth less and b's pass costs a less now b is ahead
+	second := DesirabilityDelta(1016, 1000, true)
+	deltas := []float64{16, second, DesirabilityDelta(1000, 1016+second, false)}
+	for i, decision := range decisions {
+		if math.Abs(decision.DesirabilityDelta-deltas[i]) > 1e-9 {
+			t.Errorf("decision %v delta = %v, want %v", i, decision.DesirabilityDelta, deltas[i])
+		}
+	}
+
+	// every score is the baseline plus whatever the decisions to the user added
+	for _, user := range users {
+		want := DesirabilityBaseline
+		for _, decision := range decisions {
+			if decision.ToUser == user.Id {
+				want += decision.DesirabilityDelta
+			}
+		}
+
+		if math.Abs(scores[user.Id]-want) > 1e-9 {
+			t.Errorf("score of %v = %v, want %v", user.Id, scores[user.Id], want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventLike    EventType = "like"
+	EventMatch   EventType = "match"
+	EventUnmatch EventType = "unmatch"
+)
+
+// Event is something that happened between two users that other parts of the
+// system might want to act on, the actor is the user that caused it
+type Event struct {
+	Type        EventType
+	ActorId     UUID
+	RecipientId UUID
+	CreatedAt   time.Time
+}
+
+// Involves is true if the user should be told about the event. Only the
+// recipient is told about a like but both users are told about a match
+func (e Event) Involves(user UUID) bool {
+	switch e.Type {
+	case EventLike:
+		return e.RecipientId == user
+	default:
+		return e.RecipientId == user || e.ActorId == user
+	}
+}
+
+type EventPublisher interface {
+	// Publish is called with the transaction that made the change as the last
+	// thing before it is committed, so the event can be sent as part of it
+	Publish(ctx context.Context, db DBTX, event Event) error
+}
+
+// EventBroker is an EventPublisher that can also be subscribed to, this is what
+// the Watch RPCs use to find out about new events for a user
+type EventBroker interface {
+	EventPublisher
+
+	Subscribe(user UUID) *Subscription
+	Unsubscribe(subscription *Subscription)
+}
+
+// subscriptionBufferSize is how many events can be waiting for a subscriber
+// before new events start getting dropped for it
+const subscriptionBufferSize = 64
+
+type Subscription struct {
+	UserId UUID
+	Events chan Event
+}
+
+// LocalBroker sends events to subscribers in this process only, so watchers
+// only hear about decisions made on the same server they are connected to.
+// Events published with a Tx are held until it commits so nothing hears about a
+// change that was rolled back, anything else is sent straight away
+type LocalBroker struct {
+	mutex         sync.RWMutex
+	subscriptions map[UUID]map[*Subscription]struct{}
+}
+
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{subscriptions: make(map[UUID]map[*Subscription]struct{})}
+}
+
+func (b *LocalBroker) Publish(ctx context.Context, db DBTX, event Event) error {
+	if tx, ok := db.(*Tx); ok {
+		tx.AfterCommit(func() { b.send(event) })
+		return nil
+	}
+
+	b.send(event)
+
+	return nil
+}
+
+func (b *LocalBroker) send(event Event) {
+	log.Printf("event: [type=%v] [actor=%v] [recipient=%v]", event.Type, event.ActorId, event.RecipientId)
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, user := range []UUID{event.RecipientId, event.ActorId} {
+		if !event.Involves(user) {
+			continue
+		}
+
+		for subscription := range b.subscriptions[user] {
+			// never block the publisher on a slow watcher, they will miss
+			// this event but everyone else still gets theirs
+			select {
+			case subscription.Events <- event:
+			default:
+				log.Printf("dropped %v event for slow subscriber [id=%v]", event.Type, user)
+			}
+		}
+	}
+}
+
+func (b *LocalBroker) Subscribe(user UUID) *Subscription {
+	subscription := &Subscription{
+		UserId: user,
+		Events: make(chan Event, subscriptionBufferSize),
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.subscriptions[user] == nil {
+		b.subscriptions[user] = make(map[*Subscription]struct{})
+	}
+
+	b.subscriptions[user][subscription] = struct{}{}
+
+	return subscription
+}
+
+func (b *LocalBroker) Unsubscribe(subscription *Subscription) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.subscriptions[subscription.UserId], subscription)
+
+	if len(b.subscriptions[subscription.UserId]) == 0 {
+		delete(b.subscriptions, subscription.UserId)
+	}
+}
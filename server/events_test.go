@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLocalBrokerPublishAfterCommit(t *testing.T) {
+	outer := Event{Type: EventLike, ActorId: "a", RecipientId: "b"}
+	inner := Event{Type: EventMatch, ActorId: "a", RecipientId: "b"}
+
+	tests := []struct {
+		name string
+		// run publishes and then commits or rolls back
+		run  func(ctx context.Context, broker *LocalBroker, db *fakeDB) error
+		want []Event
+	}{
+		{
+			name: "no transaction",
+			run: func(ctx context.Context, broker *LocalBroker, db *fakeDB) error {
+				return broker.Publish(ctx, db, outer)
+			},
+			want: []Event{outer},
+		},
+		{
+			name: "committed",
+			run: func(ctx context.Context, broker *LocalBroker, db *fakeDB) error {
+				tx, _ := BeginTx(ctx, db)
+				if err := broker.Publish(ctx, tx, outer); err != nil {
+					return err
+				}
+
+				if err := tx.Commit(ctx); err != nil {
+					return err
+				}
+
+				// committing again or the deferred rollback after it mustn't send it a
+				// second time
+				_ = tx.Commit(ctx)
+				_ = tx.Rollback(ctx)
+
+				return nil
+			},
+			want: []Event{outer},
+		},
+		{
+			name: "rolled back",
+			run: func(ctx context.Context, broker *LocalBroker, db *fakeDB) error {
+				tx, _ := BeginTx(ctx, db)
+				if err := broker.Publish(ctx, tx, outer); err != nil {
+					return err
+				}
+
+				return tx.Rollback(ctx)
+			},
+			want: nil,
+		},
+		{
+			name: "savepoint rolled back",
+			run: func(ctx context.Context, broker *LocalBroker, db *fakeDB) error {
+				tx, _ := BeginTx(ctx, db)
+				savepoint, _ := BeginTx(ctx, tx)
+
+				if err := broker.Publish(ctx, savepoint, inner); err != nil {
+					return err
+				}
+
+				if err := savepoint.Rollback(ctx); err != nil {
+					return err
+				}
+
+				if err := broker.Publish(ctx, tx, outer); err != nil {
+					return err
+				}
+
+				return tx.Commit(ctx)
+			},
+			want: []Event{outer},
+		},
+		{
+			name: "savepoint committed but the transaction rolled back",
+			run: func(ctx context.Context, broker *LocalBroker, db *fakeDB) error {
+				tx, _ := BeginTx(ctx, db)
+				savepoint, _ := BeginTx(ctx, tx)
+
+				if err := broker.Publish(ctx, savepoint, inner); err != nil {
+					return err
+				}
+
+				if err := savepoint.Commit(ctx); err != nil {
+					return err
+				}
+
+				return tx.Rollback(ctx)
+			},
+			want: nil,
+		},
+		{
+			name: "savepoint and transaction committed",
+			run: func(ctx context.Context, broker *LocalBroker, db *fakeDB) error {
+				tx, _ := BeginTx(ctx, db)
+				if err := broker.Publish(ctx, tx, outer); err != nil {
+					return err
+				}
+
+				savepoint, _ := BeginTx(ctx, tx)
+				if err := broker.Publish(ctx, savepoint, inner); err != nil {
+					return err
+				}
+
+				if err := savepoint.Commit(ctx); err != nil {
+					return err
+				}
+
+				return tx.Commit(ctx)
+			},
+			want: []Event{outer, inner},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			broker := NewLocalBroker()
+			subscription := broker.Subscribe("b")
+
+			if err := test.run(ctx, broker, &fakeDB{}); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			var events []Event
+			for len(subscription.Events) > 0 {
+				events = append(events, <-subscription.Events)
+			}
+
+			if len(events) != len(test.want) {
+				t.Fatalf("published %v, want %v", events, test.want)
+			}
+
+			for i := range events {
+				if events[i] != test.want[i] {
+					t.Errorf("published %v, want %v", events, test.want)
+				}
+			}
+		})
+	}
+}
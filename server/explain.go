@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+//go:embed queries/get_explain_sample.sql
+var getExplainSampleSQL string
+
+// explainSample is who the queries are explained for, Popular is the user with
+// the most likes and Liker is someone who liked them
+type explainSample struct {
+	Popular UUID
+	Liker   UUID
+}
+
+type ExplainQuery struct {
+	Name string
+	SQL  string
+	Args func(sample explainSample) []any
+
+	// NoAnalyze only gets the plan without running the query, for queries that
+	// can't run without a real row to point at
+	NoAnalyze bool
+}
+
+// ExplainQueries is every embedded query along with parameters that look like
+// what the server would really send
+var ExplainQueries = []ExplainQuery{
+	{Name: "insert_user", SQL: insertUserSQL},
+	{Name: "insert_decision", SQL: insertDecisionSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular, true} }},
+	{Name: "get_all_likes_received", SQL: getAllLikesReceivedSQL, Args: func(s explainSample) []any { return []any{s.Popular} }},
+	{Name: "get_all_likes_received_start", SQL: getAllLikesReceivedStartSQL, Args: func(s explainSample) []any { return []any{s.Popular, PaginationSize} }},
+	{Name: "get_all_likes_received_paged", SQL: getAllLikesReceivedPagedSQL, Args: func(s explainSample) []any { return []any{s.Popular, s.Liker, PaginationSize} }},
+	{Name: "get_all_likes_sent", SQL: getAllLikesSentSQL, Args: func(s explainSample) []any { return []any{s.Liker} }},
+	{Name: "get_liked_count", SQL: getLikedCountSQL, Args: func(s explainSample) []any { return []any{s.Popular} }},
+	{Name: "get_liked_count_breakdown", SQL: getLikedCountBreakdownSQL, Args: func(s explainSample) []any { return []any{s.Popular, time.Now().Add(-24 * time.Hour).UTC()} }},
+	{Name: "get_matches", SQL: getMatchesSQL, Args: func(s explainSample) []any { return []any{s.Popular} }},
+	{Name: "get_decision", SQL: getDecisionSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular} }},
+	{Name: "get_existing_decision", SQL: getExistingDecisionSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular} }},
+	{Name: "insert_block", SQL: insertBlockSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular} }},
+	{Name: "delete_block", SQL: deleteBlockSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular} }},
+	{Name: "get_block_exists", SQL: getBlockExistsSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular} }},
+	{Name: "dissolve_match", SQL: dissolveMatchSQL, Args: func(s explainSample) []any { return []any{s.Popular, s.Liker} }},
+	{Name: "insert_report", SQL: insertReportSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular, "explain"} }},
+	{Name: "insert_unmatch", SQL: insertUnmatchSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular} }},
+	{Name: "get_candidates", SQL: CandidatesSQL(ReciprocityRanker{}), Args: func(s explainSample) []any { return []any{s.Popular, true, nil, nil, PaginationSize} }},
+	{Name: "update_desirability", SQL: updateDesirabilitySQL, Args: func(s explainSample) []any { return []any{s.Popular, 1.0} }},
+	{Name: "get_all_decisions", SQL: getAllDecisionsSQL},
+	{Name: "get_all_users", SQL: getAllUsersSQL},
+	{Name: "set_desirability", SQL: setDesirabilitySQL, Args: func(s explainSample) []any { return []any{s.Popular, DesirabilityBaseline} }},
+	{Name: "set_decision_desirability_delta", SQL: setDecisionDesirabilityDeltaSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular, 0.0} }},
+	{Name: "get_desirability_scores", SQL: getDesirabilityScoresSQL},
+	{Name: "get_user", SQL: getUserSQL, Args: func(s explainSample) []any { return []any{s.Popular} }},
+	{Name: "set_user_tier", SQL: setUserTierSQL, Args: func(s explainSample) []any { return []any{s.Popular, "free"} }},
+	{Name: "get_quota", SQL: getQuotaSQL, Args: func(s explainSample) []any { return []any{s.Liker} }},
+	{Name: "take_like_quota", SQL: takeLikeQuotaSQL, Args: func(s explainSample) []any { return []any{s.Liker} }},
+	{Name: "take_rate_limit_token", SQL: takeRateLimitTokenSQL, Args: func(s explainSample) []any { return []any{"explain", 5.0, 20.0, 1} }},
+	{Name: "claim_idempotency_key", SQL: claimIdempotencyKeySQL, Args: func(s explainSample) []any {
+		return []any{s.Liker, "explain", IdempotencyKeyTTL.Seconds(), s.Popular, true}
+	}},
+	{Name: "get_idempotency_key", SQL: getIdempotencyKeySQL, Args: func(s explainSample) []any { return []any{s.Liker, "explain"} }},
+	{Name: "set_idempotency_result", SQL: setIdempotencyResultSQL, Args: func(s explainSample) []any { return []any{s.Liker, "explain", false} }},
+	{Name: "delete_expired_idempotency_keys", SQL: deleteExpiredIdempotencyKeysSQL},
+	{Name: "adjust_like_count", SQL: adjustLikeCountSQL, Args: func(s explainSample) []any { return []any{s.Popular, 1} }},
+	{Name: "adjust_pair_like_counts", SQL: adjustPairLikeCountsSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular, 1} }},
+	{Name: "get_like_counted", SQL: getLikeCountedSQL, Args: func(s explainSample) []any { return []any{s.Liker, s.Popular} }},
+	{Name: "reconcile_like_counts", SQL: reconcileLikeCountsSQL},
+	{Name: "notify_event", SQL: notifyEventSQL, Args: func(s explainSample) []any { return []any{eventChannel, EventLike, s.Liker, s.Popular} }},
+	{Name: "get_events_since", SQL: getEventsSinceSQL, Args: func(s explainSample) []any { return []any{time.Now().Add(-time.Hour).UTC()} }},
+	{Name: "insert_outbox_event", SQL: insertOutboxEventSQL, Args: func(s explainSample) []any { return []any{OutboxLikeRecorded, "{}"} }},
+	{Name: "claim_outbox_events", SQL: claimOutboxEventsSQL, Args: func(s explainSample) []any { return []any{50, 600.0} }},
+	{Name: "mark_outbox_delivered", SQL: markOutboxDeliveredSQL, Args: func(s explainSample) []any { return []any{int64(0)} }},
+	{Name: "mark_outbox_failed", SQL: markOutboxFailedSQL, Args: func(s explainSample) []any { return []any{int64(0), "explain", 1.0, 8} }},
+	{Name: "insert_webhook_subscription", SQL: insertWebhookSubscriptionSQL, Args: func(s explainSample) []any {
+		return []any{"http://localhost", "explain", []string{OutboxMatchCreated}}
+	}},
+	{Name: "get_webhook_subscriptions", SQL: getWebhookSubscriptionsSQL},
+	{Name: "delete_webhook_subscription", SQL: deleteWebhookSubscriptionSQL, Args: func(s explainSample) []any { return []any{0} }},
+	{Name: "abandon_webhook_deliveries", SQL: abandonWebhookDeliveriesSQL, Args: func(s explainSample) []any { return []any{0} }},
+	{Name: "enqueue_webhook_deliveries", SQL: enqueueWebhookDeliveriesSQL, Args: func(s explainSample) []any { return []any{OutboxMatchCreated, "{}"} }},
+	{Name: "claim_webhook_deliveries", SQL: claimWebhookDeliveriesSQL, Args: func(s explainSample) []any { return []any{20, 600.0} }},
+	// the delivery has to exist for the foreign key so this is never run
+	{Name: "insert_webhook_attempt", SQL: insertWebhookAttemptSQL, NoAnalyze: true, Args: func(s explainSample) []any { return []any{int64(0), 200, nil, 10} }},
+	{Name: "mark_webhook_delivered", SQL: markWebhookDeliveredSQL, Args: func(s explainSample) []any { return []any{int64(0), 200} }},
+	{Name: "mark_webhook_failed", SQL: markWebhookFailedSQL, Args: func(s explainSample) []any { return []any{int64(0), 500, "explain", 1.0, 8} }},
+	{Name: "get_webhook_deliveries", SQL: getWebhookDeliveriesSQL, Args: func(s explainSample) []any { return []any{false, 50} }},
+	{Name: "get_webhook_attempts", SQL: getWebhookAttemptsSQL, Args: func(s explainSample) []any { return []any{[]int64{0}} }},
+	{Name: "replay_webhook_deliveries", SQL: replayWebhookDeliveriesSQL, Args: func(s explainSample) []any { return []any{[]int64{0}, false} }},
+}
+
+var seqScanPattern = regexp.MustCompile(`Seq Scan on (\w+)`)
+var executionTimePattern = regexp.MustCompile(`Execution Time: ([0-9.]+) ms`)
+
+type ExplainResult struct {
+	Name          string
+	Plan          []string
+	SeqScans      []string
+	ExecutionTime string
+	Err           error
+}
+
+// Explain runs EXPLAIN (ANALYZE, BUFFERS) on the queries picked by name, or all of
+// them if names is empty. Every query runs in its own transaction that is always
+// rolled back so the writes don't change anything. With forceIndex sequential
+// scans are turned off, on a small database the planner will scan a whole table
+// over using an index so this shows if there is an index it could use at all
+func Explain(ctx context.Context, db DBTX, names []string, forceIndex bool) ([]ExplainResult, error) {
+	queries := ExplainQueries
+
+	if len(names) > 0 {
+		queries = nil
+
+		for _, name := range names {
+			found := false
+
+			for _, query := range ExplainQueries {
+				if query.Name == name {
+					queries = append(queries, query)
+					found = true
+				}
+			}
+
+			if !found {
+				return nil, fmt.Errorf("unknown query \"%v\"", name)
+			}
+		}
+	}
+
+	var sample explainSample
+
+	err := db.QueryRow(ctx, getExplainSampleSQL).Scan(&sample.Popular, &sample.Liker)
+	if err != nil {
+		return nil, fmt.Errorf("error finding users to explain with, are there any likes?: %v", err)
+	}
+
+	results := make([]ExplainResult, len(queries))
+
+	for i, query := range queries {
+		results[i] = explainQuery(ctx, db, query, sample, forceIndex)
+	}
+
+	return results, nil
+}
+
+func explainQuery(ctx context.Context, db DBTX, query ExplainQuery, sample explainSample, forceIndex bool) ExplainResult {
+	result := ExplainResult{Name: query.Name}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	defer tx.Rollback(ctx)
+
+	if forceIndex {
+		_, err = tx.Exec(ctx, "SET LOCAL enable_seqscan = off")
+		if err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	var args []any
+	if query.Args != nil {
+		args = query.Args(sample)
+	}
+
+	explain := "EXPLAIN (ANALYZE, BUFFERS) "
+	if query.NoAnalyze {
+		explain = "EXPLAIN "
+	}
+
+	// some queries start with a comment so the query has to go on its own line
+	rows, err := tx.Query(ctx, explain+"\n"+query.SQL, args...)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			result.Err = err
+			return result
+		}
+
+		result.Plan = append(result.Plan, line)
+
+		if match := seqScanPattern.FindStringSubmatch(line); match != nil {
+			result.SeqScans = append(result.SeqScans, match[1])
+		}
+
+		if match := executionTimePattern.FindStringSubmatch(line); match != nil {
+			result.ExecutionTime = match[1] + "ms"
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		result.Err = err
+	}
+
+	return result
+}
+
+func (r ExplainResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%-32v ERROR %v", r.Name, r.Err)
+	}
+
+	executionTime := r.ExecutionTime
+	if executionTime == "" {
+		executionTime = "not run"
+	}
+
+	if len(r.SeqScans) == 0 {
+		return fmt.Sprintf("%-32v ok        %v", r.Name, executionTime)
+	}
+
+	return fmt.Sprintf("%-32v SEQ SCAN  %v on %v", r.Name, executionTime, strings.Join(r.SeqScans, ", "))
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	plans := map[string][]any{
+		"get_block_exists": {"Index Only Scan using blocks_pkey on blocks", "Execution Time: 0.042 ms"},
+		"get_all_users":    {"Seq Scan on users  (cost=0.00..1.05 rows=5 width=16)", "Execution Time: 1.500 ms"},
+		"insert_webhook_attempt": {
+			"Insert on webhook_attempts",
+			"  ->  Nested Loop",
+			"        ->  Seq Scan on webhook_deliveries",
+			"        ->  Seq Scan on webhook_subscriptions",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		names      []string
+		forceIndex bool
+		results    []ExplainResult
+	}{
+		{
+			name:  "index scan",
+			names: []string{"get_block_exists"},
+			results: []ExplainResult{
+				{Name: "get_block_exists", Plan: []string{"Index Only Scan using blocks_pkey on blocks", "Execution Time: 0.042 ms"}, ExecutionTime: "0.042ms"},
+			},
+		},
+		{
+			name:  "seq scans",
+			names: []string{"get_all_users", "insert_webhook_attempt"},
+			results: []ExplainResult{
+				{Name: "get_all_users", Plan: []string{"Seq Scan on users  (cost=0.00..1.05 rows=5 width=16)", "Execution Time: 1.500 ms"}, SeqScans: []string{"users"}, ExecutionTime: "1.500ms"},
+				{
+					Name:     "insert_webhook_attempt",
+					Plan:     []string{"Insert on webhook_attempts", "  ->  Nested Loop", "        ->  Seq Scan on webhook_deliveries", "        ->  Seq Scan on webhook_subscriptions"},
+					SeqScans: []string{"webhook_deliveries", "webhook_subscriptions"},
+				},
+			},
+		},
+		{
+			name:       "forcing indexes",
+			names:      []string{"get_block_exists"},
+			forceIndex: true,
+			results: []ExplainResult{
+				{Name: "get_block_exists", Plan: []string{"Index Only Scan using blocks_pkey on blocks", "Execution Time: 0.042 ms"}, ExecutionTime: "0.042ms"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{
+				results: map[string][][]any{
+					getExplainSampleSQL: {{UUID("popular"), UUID("liker")}},
+				},
+			}
+
+			// each query is answered with the plan for its name
+			db.respond = func(sql string, args []any) ([][]any, bool) {
+				for _, query := range ExplainQueries {
+					if strings.HasSuffix(sql, "\n"+query.SQL) {
+						var rows [][]any
+						for _, line := range plans[query.Name] {
+							rows = append(rows, []any{line})
+						}
+
+						return rows, true
+					}
+				}
+
+				return nil, false
+			}
+
+			results, err := Explain(context.Background(), db, test.names, test.forceIndex)
+			if err != nil {
+				t.Fatalf("Explain() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(results, test.results) {
+				t.Errorf("Explain() = %+v, want %+v", results, test.results)
+			}
+
+			// nothing explained is ever kept
+			if db.rollbacks != len(test.names) || db.commits != 0 {
+				t.Errorf("committed %v and rolled back %v, want every query rolled back", db.commits, db.rollbacks)
+			}
+
+			forced := 0
+			for _, query := range db.queries {
+				if query.sql == "SET LOCAL enable_seqscan = off" {
+					forced += 1
+				}
+
+				// the insert can only be planned as there is no delivery for it to point at
+				if strings.Contains(query.sql, insertWebhookAttemptSQL) && !strings.HasPrefix(query.sql, "EXPLAIN \n") {
+					t.Errorf("insert_webhook_attempt was run with %q", strings.SplitN(query.sql, "\n", 2)[0])
+				}
+
+				if strings.Contains(query.sql, getBlockExistsSQL) && !strings.HasPrefix(query.sql, "EXPLAIN (ANALYZE, BUFFERS) \n") {
+					t.Errorf("get_block_exists was run with %q", strings.SplitN(query.sql, "\n", 2)[0])
+				}
+			}
+
+			if want := boolToInt(test.forceIndex) * len(test.names); forced != want {
+				t.Errorf("seq scans turned off %v times, want %v", forced, want)
+			}
+		})
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	db := &fakeDB{
+		results: map[string][][]any{
+			getExplainSampleSQL: {{UUID("popular"), UUID("liker")}},
+		},
+	}
+
+	if _, err := Explain(context.Background(), db, []string{"get_block_exists", "get_nothing"}, false); err == nil {
+		t.Errorf("Explain() of an unknown query error = nil")
+	}
+
+	if len(db.queries) != 0 {
+		t.Errorf("ran %v queries before finding an unknown one", len(db.queries))
+	}
+
+	// without any likes there is nobody to explain the queries for
+	if _, err := Explain(context.Background(), &fakeDB{}, nil, false); err == nil {
+		t.Errorf("Explain() without any likes error = nil")
+	}
+}
+
+func TestExplainQueries(t *testing.T) {
+	placeholder := regexp.MustCompile(`\$(\d+)`)
+	names := map[string]bool{}
+
+	for _, query := range ExplainQueries {
+		t.Run(query.Name, func(t *testing.T) {
+			if names[query.Name] {
+				t.Errorf("%v is explained twice", query.Name)
+			}
+
+			names[query.Name] = true
+
+			// the query would fail to run with a parameter missing or left over
+			parameters := 0
+			for _, match := range placeholder.FindAllStringSubmatch(query.SQL, -1) {
+				n, _ := strconv.Atoi(match[1])
+				parameters = max(parameters, n)
+			}
+
+			var args []any
+			if query.Args != nil {
+				args = query.Args(explainSample{Popular: "popular", Liker: "liker"})
+			}
+
+			if len(args) != parameters {
+				t.Errorf("%v is explained with %v args, want %v", query.Name, len(args), parameters)
+			}
+		})
+	}
+}
+
+func TestExplainResultString(t *testing.T) {
+	tests := []struct {
+		name   string
+		result ExplainResult
+		output string
+	}{
+		{
+			name:   "index scan",
+			result: ExplainResult{Name: "get_block_exists", ExecutionTime: "0.042ms"},
+			output: "get_block_exists                 ok        0.042ms",
+		},
+		{
+			name:   "not run",
+			result: ExplainResult{Name: "insert_webhook_attempt"},
+			output: "insert_webhook_attempt           ok        not run",
+		},
+		{
+			name:   "seq scans",
+			result: ExplainResult{Name: "get_all_users", ExecutionTime: "1.500ms", SeqScans: []string{"users", "decisions"}},
+			output: "get_all_users                    SEQ SCAN  1.500ms on users, decisions",
+		},
+		{
+			name:   "error",
+			result: ExplainResult{Name: "get_all_users", Err: context.Canceled},
+			output: "get_all_users                    ERROR context canceled",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if output := test.result.String(); output != test.output {
+				t.Errorf("String() = %q, want %q", output, test.output)
+			}
+		})
+	}
+}
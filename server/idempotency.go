@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"log"
+	"time"
+)
+
+//go:embed queries/claim_idempotency_key.sql
+var claimIdempotencyKeySQL string
+
+//go:embed queries/get_idempotency_key.sql
+var getIdempotencyKeySQL string
+
+//go:embed queries/set_idempotency_result.sql
+var setIdempotencyResultSQL string
+
+//go:embed queries/delete_expired_idempotency_keys.sql
+var deleteExpiredIdempotencyKeysSQL string
+
+const (
+	// IdempotencyKeyTTL is how long a key is remembered for, a retry after this is
+	// treated as a new request
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	MaxIdempotencyKeyLength = 255
+
+	idempotencyCleanupInterval = 1 * time.Hour
+)
+
+// IdempotencyRecord is what was stored for a key the first time it was used, the
+// recipient and liked are kept so a key reused for a different decision is caught
+type IdempotencyRecord struct {
+	RecipientId UUID
+	Liked       bool
+	MutualLikes bool
+}
+
+// ClaimIdempotencyKey stores the key for the decision if it hasn't been used or
+// has expired and returns true. If another request is using the same key right
+// now this waits for its transaction to finish. When false is returned the key
+// was already used and record is filled in with what was stored for it
+func ClaimIdempotencyKey(ctx context.Context, db DBTX, decision Decision, key string, record *IdempotencyRecord) (bool, error) {
+	var claimed bool
+
+	err := db.
+		QueryRow(ctx, claimIdempotencyKeySQL, decision.FromUser, key, IdempotencyKeyTTL.Seconds(), decision.ToUser, decision.Liked).
+		Scan(&claimed)
+	if err == nil {
+		return true, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, err
+	}
+
+	var mutualLikes *bool
+
+	err = db.QueryRow(ctx, getIdempotencyKeySQL, decision.FromUser, key).Scan(&record.RecipientId, &record.Liked, &mutualLikes)
+	if err != nil {
+		return false, err
+	}
+
+	// the result is set in the same transaction as the claim so this should
+	// never be nil, but if it is there was no match to report
+	if mutualLikes != nil {
+		record.MutualLikes = *mutualLikes
+	}
+
+	return false, nil
+}
+
+func SetIdempotencyResult(ctx context.Context, db DBTX, decision Decision, key string, mutualLikes bool) error {
+	_, err := db.Exec(ctx, setIdempotencyResultSQL, decision.FromUser, key, mutualLikes)
+	return err
+}
+
+func DeleteExpiredIdempotencyKeys(ctx context.Context, db DBTX) (int64, error) {
+	tag, err := db.Exec(ctx, deleteExpiredIdempotencyKeysSQL)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// CleanupIdempotencyKeys deletes expired keys every hour until ctx is done.
+// Expired keys are already ignored so this only stops the table growing forever
+func CleanupIdempotencyKeys(ctx context.Context, connect func(ctx context.Context) (*pgx.Conn, error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(idempotencyCleanupInterval):
+		}
+
+		conn, err := connect(ctx)
+		if err != nil {
+			log.Printf("error cleaning up idempotency keys: %v", err)
+			continue
+		}
+
+		deleted, err := DeleteExpiredIdempotencyKeys(ctx, conn)
+		if err != nil {
+			log.Printf("error cleaning up idempotency keys: %v", err)
+		} else {
+			log.Printf("deleted %v expired idempotency keys", deleted)
+		}
+
+		conn.Close(context.Background())
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyDecisionIdempotency(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	from := UUID("from")
+	to := UUID("to")
+
+	tests := []struct {
+		name string
+		key  *string
+		// record is what was stored for the key before, nil if it is unused
+		record   []any
+		code     codes.Code
+		replayed bool
+		mutual   bool
+		claims   int
+		// applied is whether the decision went through and its result was stored
+		applied bool
+	}{
+		{name: "no key", key: nil, code: codes.OK, claims: 0, applied: true},
+		{name: "new key", key: ptr("key"), code: codes.OK, claims: 1, applied: true},
+		{name: "retry", key: ptr("key"), record: []any{to, true, ptr(true)}, code: codes.OK, replayed: true, mutual: true, claims: 1},
+		{name: "retry before a result was stored", key: ptr("key"), record: []any{to, true, (*bool)(nil)}, code: codes.OK, replayed: true, claims: 1},
+		{name: "key used for someone else", key: ptr("key"), record: []any{UUID("other"), true, ptr(false)}, code: codes.InvalidArgument, claims: 1},
+		{name: "key used for a pass", key: ptr("key"), record: []any{to, false, ptr(false)}, code: codes.InvalidArgument, claims: 1},
+		{name: "empty key", key: ptr(""), code: codes.InvalidArgument, claims: 0},
+		{name: "key too long", key: ptr(strings.Repeat("k", MaxIdempotencyKeyLength+1)), code: codes.InvalidArgument, claims: 0},
+		{name: "longest key", key: ptr(strings.Repeat("k", MaxIdempotencyKeyLength)), code: codes.OK, claims: 1, applied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{
+				results: map[string][][]any{
+					insertDecisionSQL:    {{1, now, from, to, true}},
+					getBlockExistsSQL:    {{false}},
+					getLikeCountedSQL:    {{true}},
+					takeLikeQuotaSQL:     {{1}},
+					getQuotaSQL:          {{"free", 10, 10}},
+					insertOutboxEventSQL: {{int64(1)}},
+				},
+			}
+
+			// a used key isnt claimed again and what was stored for it is read instead
+			if test.record != nil {
+				db.results[getIdempotencyKeySQL] = [][]any{test.record}
+			} else {
+				db.results[claimIdempotencyKeySQL] = [][]any{{true}}
+			}
+
+			server := ExploreServer{Events: NewLocalBroker()}
+
+			request := &explore.PutDecisionRequest{
+				ActorUserId:     string(from),
+				RecipientUserId: string(to),
+				LikedRecipient:  true,
+				IdempotencyKey:  test.key,
+			}
+
+			response, err := server.applyDecision(context.Background(), db, request)
+			if code := status.Code(err); code != test.code {
+				t.Fatalf("applyDecision() = %v, want %v", err, test.code)
+			}
+
+			if err == nil && (response.Replayed != test.replayed || response.MutualLikes != test.mutual) {
+				t.Errorf("applyDecision() = %+v, want replayed %v and mutual likes %v", response, test.replayed, test.mutual)
+			}
+
+			if claims := len(db.ran(claimIdempotencyKeySQL)); claims != test.claims {
+				t.Errorf("key claimed %v times, want %v", claims, test.claims)
+			}
+
+			// a replay or a rejected key changes nothing and takes no quota
+			inserts := len(db.ran(insertDecisionSQL))
+			takes := len(db.ran(takeLikeQuotaSQL))
+			if want := boolToInt(test.applied); inserts != want || takes != want {
+				t.Errorf("decision recorded %v times and quota taken %v times, want %v", inserts, takes, want)
+			}
+
+			results := db.ran(setIdempotencyResultSQL)
+			if want := boolToInt(test.applied && test.key != nil); len(results) != want {
+				t.Fatalf("result stored %v times, want %v", len(results), want)
+			}
+
+			if len(results) > 0 && results[0].args[1] != *test.key {
+				t.Errorf("result stored for key %v, want %v", results[0].args[1], *test.key)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"log"
+)
+
+//go:embed queries/adjust_like_count.sql
+var adjustLikeCountSQL string
+
+//go:embed queries/adjust_pair_like_counts.sql
+var adjustPairLikeCountsSQL string
+
+//go:embed queries/get_like_counted.sql
+var getLikeCountedSQL string
+
+//go:embed queries/reconcile_like_counts.sql
+var reconcileLikeCountsSQL string
+
+// The number of likes each user has received is kept in like_counts so
+// CountLikedYou doesn't need to count every decision. It is the same as counting
+// the likes to the user that aren't between blocked users and weren't made before
+// an unmatch between them, and has to be kept up to date in the same transaction
+// as anything that changes that:
+//   - PutDecision when a like is added or a counted like is turned into a pass
+//   - BlockUser and UnblockUser when the likes between the two are hidden or shown
+//   - Unmatch as it turns one like into a pass and hides the other, and BlockUser
+//     when it dissolves a match
+//
+// If it does drift ReconcileLikeCounts recounts everything and fixes it
+
+func AdjustLikeCount(ctx context.Context, db DBTX, user User, delta int) error {
+	_, err := db.Exec(ctx, adjustLikeCountSQL, user.Id, delta)
+	return err
+}
+
+// IsLikeCounted is whether there is a like from one user to the other that is
+// in the recipients count
+func IsLikeCounted(ctx context.Context, db DBTX, from User, to User) (bool, error) {
+	var counted bool
+	err := db.QueryRow(ctx, getLikeCountedSQL, from.Id, to.Id).Scan(&counted)
+	return counted, err
+}
+
+// AdjustPairLikeCounts adds delta to the count of a and b for each like between them
+func AdjustPairLikeCounts(ctx context.Context, db DBTX, a User, b User, delta int) error {
+	_, err := db.Exec(ctx, adjustPairLikeCountsSQL, a.Id, b.Id, delta)
+	return err
+}
+
+type ReconcileResult struct {
+	Users      int
+	Drifted    int
+	TotalDrift int64
+	MaxDrift   int64
+}
+
+// ReconcileLikeCounts recounts the likes for every user and fixes any cached
+// count that is wrong. The table is locked while it runs so a decision being
+// made at the same time can't be counted twice or missed
+func ReconcileLikeCounts(ctx context.Context, db DBTX) (ReconcileResult, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "LOCK TABLE like_counts IN EXCLUSIVE MODE")
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+
+	var result ReconcileResult
+
+	err = tx.
+		QueryRow(ctx, reconcileLikeCountsSQL).
+		Scan(&result.Users, &result.Drifted, &result.TotalDrift, &result.MaxDrift)
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+
+	log.Printf("reconciled like counts: [users=%v] [drifted=%v] [total drift=%v] [max drift=%v]", result.Users, result.Drifted, result.TotalDrift, result.MaxDrift)
+
+	return result, nil
+}
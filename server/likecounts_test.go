@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBlockUnblockLikeCounts(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	blocker := User{Id: "blocker"}
+	blocked := User{Id: "blocked"}
+
+	tests := []struct {
+		name    string
+		block   bool
+		blocked bool
+		// removed is whether unblocking found a block to delete
+		removed bool
+		// delta is what the pair adjustment was run with, 0 if it wasnt
+		delta int
+	}{
+		{name: "block", block: true, blocked: false, delta: -1},
+		{name: "block when they already blocked the blocker", block: true, blocked: true, delta: 0},
+		{name: "unblock", block: false, blocked: false, removed: true, delta: 1},
+		{name: "unblock when they still block the blocker", block: false, blocked: true, removed: true, delta: 0},
+		{name: "unblock someone who wasnt blocked", block: false, blocked: false, removed: false, delta: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{
+				results: map[string][][]any{
+					getBlockExistsSQL: {{test.blocked}},
+					getLikeCountedSQL: {{false}},
+					insertUnmatchSQL:  {{1, now, blocker.Id, blocked.Id}},
+				},
+				affected: map[string]int64{},
+			}
+
+			if test.removed {
+				db.affected[deleteBlockSQL] = 1
+			}
+
+			var err error
+			if test.block {
+				_, err = BlockUser(context.Background(), db, blocker, blocked, &Unmatch{})
+			} else {
+				err = UnblockUser(context.Background(), db, blocker, blocked)
+			}
+
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			adjustments := db.ran(adjustPairLikeCountsSQL)
+			if len(adjustments) != boolToInt(test.delta != 0) {
+				t.Fatalf("pair adjusted %v times, want %v", len(adjustments), boolToInt(test.delta != 0))
+			}
+
+			if len(adjustments) > 0 {
+				want := []any{blocker.Id, blocked.Id, test.delta}
+				if !reflect.DeepEqual(adjustments[0].args, want) {
+					t.Errorf("pair adjusted with %v, want %v", adjustments[0].args, want)
+				}
+			}
+
+			// the adjustment has to be in the same transaction as the block
+			if db.commits != 1 {
+				t.Errorf("committed %v times, want 1", db.commits)
+			}
+		})
+	}
+}
+
+func TestReconcileLikeCounts(t *testing.T) {
+	db := &fakeDB{
+		results: map[string][][]any{
+			reconcileLikeCountsSQL: {{10, 2, int64(5), int64(4)}},
+		},
+	}
+
+	result, err := ReconcileLikeCounts(context.Background(), db)
+	if err != nil {
+		t.Fatalf("ReconcileLikeCounts() error = %v", err)
+	}
+
+	want := ReconcileResult{Users: 10, Drifted: 2, TotalDrift: 5, MaxDrift: 4}
+	if result != want {
+		t.Errorf("ReconcileLikeCounts() = %+v, want %+v", result, want)
+	}
+
+	// nothing can change the counts between locking them and the recount
+	if len(db.queries) != 2 || db.queries[0].sql != "LOCK TABLE like_counts IN EXCLUSIVE MODE" || db.commits != 1 {
+		t.Errorf("ran %v and committed %v times, want the lock then the recount in one transaction", db.queries, db.commits)
+	}
+}
@@ -11,9 +11,11 @@ import (
 	"math/rand/v2"
 	"net"
 	"os"
+	"strconv"
+	"time"
 )
 
-func GenerateUsers(ctx context.Context, db *pgx.Conn, count int) ([]User, error) {
+func GenerateUsers(ctx context.Context, db DBTX, count int) ([]User, error) {
 	users := make([]User, count)
 
 	for i := 0; i < count; i++ {
@@ -22,6 +24,18 @@ func GenerateUsers(ctx context.Context, db *pgx.Conn, count int) ([]User, error)
 			return nil, err
 		}
 
+		// some users pay for premium which gives them more likes per day
+		if rand.Float64() < 0.2 {
+			err = SetUserTier(ctx, db, user, "premium")
+			if err != nil {
+				return nil, err
+			}
+
+			user.Tier = "premium"
+		} else {
+			user.Tier = "free"
+		}
+
 		users[i] = user
 	}
 
@@ -30,7 +44,7 @@ func GenerateUsers(ctx context.Context, db *pgx.Conn, count int) ([]User, error)
 	return users, nil
 }
 
-func GenerateDecisions(ctx context.Context, db *pgx.Conn, users []User) ([]Decision, error) {
+func GenerateDecisions(ctx context.Context, db DBTX, users []User) ([]Decision, error) {
 	// there are three states for a decision for any give "from" user and any given "to user"
 	// 1. the from user liked the other to user
 	// 2. the from user passed on the to user
@@ -76,24 +90,49 @@ func GenerateDecisions(ctx context.Context, db *pgx.Conn, users []User) ([]Decis
 	return decisions, nil
 }
 
-func RunServer(server *ExploreServer) error {
+// RunServer serves the explore service and the admin service on their own
+// listeners, the admin service has no auth so it is kept off the public port
+// and only ever listens on localhost
+func RunServer(server *ExploreServer, admin *AdminServer) error {
 	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%v", server.Port))
 	if err != nil {
 		return err
 	}
 
-	log.Printf(fmt.Sprintf("listening on %v\n", listener.Addr().String()))
+	log.Printf("listening on %v\n", listener.Addr().String())
+
+	adminListener, err := net.Listen("tcp", fmt.Sprintf("localhost:%v", admin.Port))
+	if err != nil {
+		listener.Close()
+		return err
+	}
+
+	log.Printf("admin listening on %v\n", adminListener.Addr().String())
 
 	var opts []grpc.ServerOption
+
+	if server.RateLimiter != nil {
+		opts = append(opts, grpc.ChainUnaryInterceptor(RateLimitInterceptor(server.RateLimiter, DefaultRateLimits)))
+	}
+
 	grpcServer := grpc.NewServer(opts...)
 	explore.RegisterExploreServiceServer(grpcServer, server)
 
-	err = grpcServer.Serve(listener)
-	if err != nil {
-		return err
-	}
+	adminServer := grpc.NewServer()
+	explore.RegisterAdminServiceServer(adminServer, admin)
 
-	return nil
+	// whichever stops first takes the other down with it
+	errs := make(chan error, 2)
+
+	go func() { errs <- grpcServer.Serve(listener) }()
+	go func() { errs <- adminServer.Serve(adminListener) }()
+
+	err = <-errs
+
+	grpcServer.Stop()
+	adminServer.Stop()
+
+	return err
 }
 
 func WriteClientId(users []User) error {
@@ -121,7 +160,7 @@ func main() {
 	log.SetPrefix("[SERVER]: ")
 
 	/* connect to postgres */
-	db, err := ConnectToDB(ctx,
+	db, err := ConnectToPool(ctx,
 		os.Getenv("POSTGRES_HOST"),
 		os.Getenv("POSTGRES_USER"),
 		os.Getenv("POSTGRES_PASSWORD"),
@@ -133,9 +172,19 @@ func main() {
 		log.Fatalf("failed to connect to database: %v", err)
 	}
 
-	log.Printf("connected to database %v\n", db.PgConn().Conn().RemoteAddr().String())
+	log.Printf("connected to database %v:%v\n", db.Config().ConnConfig.Host, db.Config().ConnConfig.Port)
 
-	defer db.Close(ctx)
+	defer db.Close()
+
+	/* Run an admin command instead of the server if one was given */
+	if len(os.Args) > 1 {
+		err = RunCommand(ctx, db, os.Args[1], os.Args[2:])
+		if err != nil {
+			log.Fatalf("error while running command %v: %v", os.Args[1], err)
+		}
+
+		return
+	}
 
 	/* Generate users and decisions */
 	users, err := GenerateUsers(ctx, db, 50)
@@ -143,24 +192,169 @@ func main() {
 		log.Fatalf("error while generating users: %v", err)
 	}
 
-	_, err = GenerateDecisions(ctx, db, users)
+	decisions, err := GenerateDecisions(ctx, db, users)
 	if err != nil {
 		log.Fatalf("error while generating decisions: %v", err)
 	}
 
+	// generated decisions go straight into the database so the scores and like
+	// counts need to be built up from them before anything uses them. Only the
+	// new users are scored, everyone else keeps the score PutDecision built up
+	err = SeedDesirability(ctx, db, users, decisions)
+	if err != nil {
+		log.Fatalf("error while computing desirability: %v", err)
+	}
+
+	_, err = ReconcileLikeCounts(ctx, db)
+	if err != nil {
+		log.Fatalf("error while counting likes: %v", err)
+	}
+
 	/* Create client ID file */
 	err = WriteClientId(users)
 	if err != nil {
 		log.Fatalf("error while writing client id file: %v", err)
 	}
 
+	/* Create the rate limiter */
+	rateLimiter, err := NewRateLimiter(os.Getenv("RATE_LIMIT_BACKEND"), db)
+	if err != nil {
+		log.Fatalf("error while creating rate limiter: %v", err)
+	}
+
+	/* Route reads to the replica if there is one */
+	var replica DBTX
+
+	if replicaHost := os.Getenv("POSTGRES_REPLICA_HOST"); replicaHost != "" {
+		replicaPort := os.Getenv("POSTGRES_REPLICA_PORT")
+		if replicaPort == "" {
+			replicaPort = os.Getenv("POSTGRES_PORT")
+		}
+
+		pool, err := ConnectToReplica(ctx,
+			replicaHost,
+			os.Getenv("POSTGRES_USER"),
+			os.Getenv("POSTGRES_PASSWORD"),
+			os.Getenv("POSTGRES_DB"),
+			replicaPort,
+		)
+		if err != nil {
+			log.Fatalf("failed to connect to replica: %v", err)
+		}
+
+		defer pool.Close()
+
+		replica = pool
+	}
+
+	stickySeconds := 0
+	if value := os.Getenv("REPLICA_STICKY_SECONDS"); value != "" {
+		stickySeconds, err = strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("invalid replica sticky seconds \"%v\": %v", value, err)
+		}
+	}
+
+	reads := NewReadRouter(db, replica, time.Duration(stickySeconds)*time.Second)
+
+	/* Create the cache */
+	cacheSize := DefaultCacheSize
+	if value := os.Getenv("CACHE_SIZE"); value != "" {
+		cacheSize, err = strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("invalid cache size \"%v\": %v", value, err)
+		}
+	}
+
+	cacheTTL := DefaultCacheTTL
+	if value := os.Getenv("CACHE_TTL"); value != "" {
+		cacheTTL, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("invalid cache ttl \"%v\": %v", value, err)
+		}
+	}
+
+	cache, err := NewCache(os.Getenv("CACHE_BACKEND"), os.Getenv("CACHE_ADDRESS"), cacheSize)
+	if err != nil {
+		log.Fatalf("error while creating cache: %v", err)
+	}
+
+	likes := NewLikesCache(cache, cacheTTL)
+
+	// background workers need their own connections as they hold them for a
+	// long time, this opens a new one outside of the pool
+	connect := func(ctx context.Context) (*pgx.Conn, error) {
+		return ConnectToDB(ctx,
+			os.Getenv("POSTGRES_HOST"),
+			os.Getenv("POSTGRES_USER"),
+			os.Getenv("POSTGRES_PASSWORD"),
+			os.Getenv("POSTGRES_DB"),
+			os.Getenv("POSTGRES_PORT"),
+		)
+	}
+
+	/* Create the event broker */
+	var events EventBroker
+
+	switch os.Getenv("EVENT_BROKER") {
+	case "", "local":
+		events = NewLocalBroker()
+	case "postgres":
+		broker := NewPostgresBroker(connect)
+
+		go broker.Listen(ctx)
+
+		events = broker
+	default:
+		log.Fatalf("unknown event broker \"%v\"", os.Getenv("EVENT_BROKER"))
+	}
+
+	/* Start relaying the outbox */
+	if sinkKind := os.Getenv("OUTBOX_SINK"); sinkKind != "" {
+		sink, err := NewOutboxSink(sinkKind, os.Getenv("OUTBOX_SINK_TARGET"))
+		if err != nil {
+			log.Fatalf("error while creating outbox sink: %v", err)
+		}
+
+		go NewOutboxRelay(sink, connect).Run(ctx)
+	}
+
+	/* Start sending webhooks */
+	go NewWebhookDispatcher(connect).Run(ctx)
+
+	/* Forget old idempotency keys */
+	go CleanupIdempotencyKeys(ctx, connect)
+
+	/* Check the ranking strategy */
+	defaultRanking := os.Getenv("RANKING_STRATEGY")
+	if _, err := RankerByName(defaultRanking); err != nil {
+		log.Fatalf("error while checking ranking strategy: %v", err)
+	}
+
 	/* Run the grpc server */
 	server := ExploreServer{
-		Port:     os.Getenv("SERVER_PORT"),
+		Port:        os.Getenv("SERVER_PORT"),
+		Database:    db,
+		RateLimiter: rateLimiter,
+		Events:      events,
+		Likes:       likes,
+		Reads:       reads,
+
+		DefaultRanking: defaultRanking,
+	}
+
+	adminPort := os.Getenv("ADMIN_PORT")
+	if adminPort == "" {
+		adminPort = DefaultAdminPort
+	}
+
+	admin := AdminServer{
+		Port:     adminPort,
 		Database: db,
+		Likes:    likes,
 	}
 
-	err = RunServer(&server)
+	err = RunServer(&server, &admin)
 	if err != nil {
 		log.Fatalf("the server encountered an error: %v", err)
 	}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log"
+	"time"
+)
+
+//go:embed queries/notify_event.sql
+var notifyEventSQL string
+
+//go:embed queries/get_events_since.sql
+var getEventsSinceSQL string
+
+const eventChannel = "explore_events"
+
+const (
+	listenRetryDelay    = 1 * time.Second
+	listenMaxRetryDelay = 30 * time.Second
+
+	// NOW() in postgres is when the transaction started not when it committed,
+	// so an event can show up with a time a little before one we already have
+	catchUpWindow = 5 * time.Second
+)
+
+// PostgresBroker sends events with pg_notify as part of the transaction that
+// made the change, so they are only sent if it commits. Every server listens on
+// its own connection and passes what it hears to the local subscribers, which
+// means watchers hear about decisions made on any server
+type PostgresBroker struct {
+	Local *LocalBroker
+
+	// Connect opens the connection used for LISTEN, this can't be the connection
+	// used for queries as it is waiting on notifications the whole time
+	Connect func(ctx context.Context) (*pgx.Conn, error)
+}
+
+type eventPayload struct {
+	Type        EventType `json:"type"`
+	ActorId     UUID      `json:"actor_id"`
+	RecipientId UUID      `json:"recipient_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewPostgresBroker(connect func(ctx context.Context) (*pgx.Conn, error)) *PostgresBroker {
+	return &PostgresBroker{
+		Local:   NewLocalBroker(),
+		Connect: connect,
+	}
+}
+
+func (b *PostgresBroker) Publish(ctx context.Context, db DBTX, event Event) error {
+	_, err := db.Exec(ctx, notifyEventSQL, eventChannel, event.Type, event.ActorId, event.RecipientId)
+	return err
+}
+
+func (b *PostgresBroker) Subscribe(user UUID) *Subscription {
+	return b.Local.Subscribe(user)
+}
+
+func (b *PostgresBroker) Unsubscribe(subscription *Subscription) {
+	b.Local.Unsubscribe(subscription)
+}
+
+// Listen passes every event from postgres to the local subscribers until ctx is
+// done. If the connection drops it reconnects and then replays anything that
+// happened while it was gone from the tables, so some events may be sent twice
+func (b *PostgresBroker) Listen(ctx context.Context) {
+	var since time.Time
+	retryDelay := listenRetryDelay
+
+	for {
+		connected, err := b.listen(ctx, &since)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if connected {
+			retryDelay = listenRetryDelay
+		}
+
+		log.Printf("event listener stopped, retrying in %v: %v", retryDelay, err)
+
+		time.Sleep(retryDelay)
+		retryDelay = min(retryDelay*2, listenMaxRetryDelay)
+	}
+}
+
+func (b *PostgresBroker) listen(ctx context.Context, since *time.Time) (bool, error) {
+	conn, err := b.Connect(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, fmt.Sprintf("LISTEN %v", eventChannel))
+	if err != nil {
+		return false, err
+	}
+
+	var listeningAt time.Time
+
+	err = conn.QueryRow(ctx, "SELECT NOW()").Scan(&listeningAt)
+	if err != nil {
+		return false, err
+	}
+
+	// the first time there is nothing to catch up on, after that anything since
+	// the last event we heard about might have been missed
+	if !since.IsZero() {
+		err = b.catchUp(ctx, conn, since.Add(-catchUpWindow))
+		if err != nil {
+			return true, err
+		}
+	}
+
+	*since = listeningAt
+
+	log.Printf("listening for events on \"%v\"", eventChannel)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+
+		var payload eventPayload
+
+		err = json.Unmarshal([]byte(notification.Payload), &payload)
+		if err != nil {
+			log.Printf("error decoding event \"%v\": %v", notification.Payload, err)
+			continue
+		}
+
+		if payload.CreatedAt.After(*since) {
+			*since = payload.CreatedAt
+		}
+
+		_ = b.Local.Publish(ctx, nil, Event(payload))
+	}
+}
+
+func (b *PostgresBroker) catchUp(ctx context.Context, db DBTX, since time.Time) error {
+	rows, err := db.Query(ctx, getEventsSinceSQL, since.UTC())
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	count := 0
+
+	for rows.Next() {
+		var event Event
+		if err := rows.Scan(&event.Type, &event.ActorId, &event.RecipientId, &event.CreatedAt); err != nil {
+			return err
+		}
+
+		_ = b.Local.Publish(ctx, nil, event)
+		count += 1
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	log.Printf("caught up on %v events since %v", count, since)
+
+	return nil
+}
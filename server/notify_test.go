@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCatchUp(t *testing.T) {
+	since := time.Date(2024, 6, 15, 14, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	at := since.Add(time.Minute).UTC()
+
+	tests := []struct {
+		name   string
+		rows   [][]any
+		events []Event
+	}{
+		{name: "nothing missed", rows: nil, events: nil},
+		{
+			name: "like and the match it made",
+			rows: [][]any{
+				{string(EventLike), UUID("a"), UUID("b"), at},
+				{string(EventMatch), UUID("a"), UUID("b"), at},
+			},
+			events: []Event{
+				{Type: EventLike, ActorId: "a", RecipientId: "b", CreatedAt: at},
+				{Type: EventMatch, ActorId: "a", RecipientId: "b", CreatedAt: at},
+			},
+		},
+		{
+			name:   "unmatch",
+			rows:   [][]any{{string(EventUnmatch), UUID("b"), UUID("a"), at}},
+			events: []Event{{Type: EventUnmatch, ActorId: "b", RecipientId: "a", CreatedAt: at}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{rows: test.rows}
+			broker := &PostgresBroker{Local: NewLocalBroker()}
+
+			// b is involved in every event so it should hear all of them
+			subscription := broker.Local.Subscribe("b")
+
+			if err := broker.catchUp(context.Background(), db, since); err != nil {
+				t.Fatalf("catchUp() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(db.args, []any{since.UTC()}) {
+				t.Errorf("query args = %v, want since in utc", db.args)
+			}
+
+			var events []Event
+			for len(subscription.Events) > 0 {
+				events = append(events, <-subscription.Events)
+			}
+
+			if !reflect.DeepEqual(events, test.events) {
+				t.Errorf("published %v, want %v", events, test.events)
+			}
+		})
+	}
+
+	// created_at is when a pair was first decided, a pass that was changed to a
+	// like keeps it so would never be caught up on
+	if strings.Contains(getEventsSinceSQL, "decisions.created_at") {
+		t.Errorf("likes are caught up on by created_at rather than decided_at")
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+//go:embed queries/insert_outbox_event.sql
+var insertOutboxEventSQL string
+
+//go:embed queries/claim_outbox_events.sql
+var claimOutboxEventsSQL string
+
+//go:embed queries/mark_outbox_delivered.sql
+var markOutboxDeliveredSQL string
+
+//go:embed queries/mark_outbox_failed.sql
+var markOutboxFailedSQL string
+
+// The outbox holds a durable record of everything other systems might care
+// about. Events are written in the same transaction as the change that caused
+// them and a relay delivers them afterwards, so an event is never lost and
+// never sent for a change that was rolled back
+const (
+	OutboxLikeRecorded    = "LikeRecorded"
+	OutboxPassRecorded    = "PassRecorded"
+	OutboxMatchCreated    = "MatchCreated"
+	OutboxUnmatchRecorded = "UnmatchRecorded"
+)
+
+type OutboxEvent struct {
+	Id        int64
+	CreatedAt time.Time
+	Type      string
+	Payload   json.RawMessage
+	Attempts  int
+}
+
+// DecisionPayload is the payload for every outbox event that comes from PutDecision
+type DecisionPayload struct {
+	ActorId     UUID      `json:"actor_id"`
+	RecipientId UUID      `json:"recipient_id"`
+	DecisionId  int       `json:"decision_id"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+type UnmatchPayload struct {
+	ActorId     UUID      `json:"actor_id"`
+	RecipientId UUID      `json:"recipient_id"`
+	UnmatchId   int       `json:"unmatch_id,omitempty"`
+	OccurredAt  time.Time `json:"occurred_at"`
+
+	// Blocked is set when the match was dissolved by a block rather than the
+	// Unmatch RPC
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+func InsertOutboxEvent(ctx context.Context, db DBTX, eventType string, payload any) (int64, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+
+	err = db.QueryRow(ctx, insertOutboxEventSQL, eventType, string(encoded)).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// OutboxSink is where the relay delivers events to. Delivery is at-least-once so
+// a sink can see the same event more than once and should use the id to tell
+type OutboxSink interface {
+	Deliver(ctx context.Context, event OutboxEvent) error
+}
+
+type outboxMessage struct {
+	Id        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+func encodeOutboxEvent(event OutboxEvent) ([]byte, error) {
+	return json.Marshal(outboxMessage{
+		Id:        event.Id,
+		Type:      event.Type,
+		CreatedAt: event.CreatedAt,
+		Payload:   event.Payload,
+	})
+}
+
+func NewOutboxSink(kind string, target string) (OutboxSink, error) {
+	switch kind {
+	case "stdout":
+		return StdoutSink{}, nil
+	case "file":
+		if target == "" {
+			return nil, fmt.Errorf("file outbox sink needs a path")
+		}
+
+		return &FileSink{Path: target}, nil
+	case "http":
+		if target == "" {
+			return nil, fmt.Errorf("http outbox sink needs a url")
+		}
+
+		return &HTTPSink{URL: target, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+	default:
+		return nil, fmt.Errorf("unknown outbox sink \"%v\"", kind)
+	}
+}
+
+// StdoutSink prints every event as a line of JSON
+type StdoutSink struct{}
+
+func (s StdoutSink) Deliver(ctx context.Context, event OutboxEvent) error {
+	encoded, err := encodeOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(os.Stdout, "%s\n", encoded)
+	return err
+}
+
+// FileSink appends every event to a file as a line of JSON
+type FileSink struct {
+	Path string
+
+	mutex sync.Mutex
+}
+
+func (s *FileSink) Deliver(ctx context.Context, event OutboxEvent) error {
+	encoded, err := encodeOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(append(encoded, '\n'))
+	return err
+}
+
+// HTTPSink POSTs every event as JSON, anything other than a 2xx is a failure
+type HTTPSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *HTTPSink) Deliver(ctx context.Context, event OutboxEvent) error {
+	encoded, err := encodeOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("%v responded with %v", s.URL, response.Status)
+	}
+
+	return nil
+}
+
+// OutboxRelay polls the outbox and delivers anything waiting to the sink. Failed
+// deliveries are retried with an exponential backoff and once an event has failed
+// MaxAttempts times it is dead lettered, it stays in the table but is never retried
+type OutboxRelay struct {
+	Sink         OutboxSink
+	BatchSize    int
+	PollInterval time.Duration
+	MaxAttempts  int
+	RetryDelay   time.Duration
+	MaxDelay     time.Duration
+
+	// ClaimFor is how long a batch is kept from other relays while it is being
+	// sent, it needs to be longer than sending a whole batch can take
+	ClaimFor time.Duration
+
+	// Connect opens the connection the relay uses
+	Connect func(ctx context.Context) (*pgx.Conn, error)
+}
+
+func NewOutboxRelay(sink OutboxSink, connect func(ctx context.Context) (*pgx.Conn, error)) *OutboxRelay {
+	return &OutboxRelay{
+		Sink:         sink,
+		BatchSize:    50,
+		PollInterval: 1 * time.Second,
+		MaxAttempts:  8,
+		RetryDelay:   1 * time.Second,
+		MaxDelay:     10 * time.Minute,
+		ClaimFor:     10 * time.Minute,
+		Connect:      connect,
+	}
+}
+
+// Run relays events until ctx is done, reconnecting if the connection is lost
+func (r *OutboxRelay) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := r.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("outbox relay stopped, retrying in %v: %v", r.PollInterval, err)
+		time.Sleep(r.PollInterval)
+	}
+}
+
+func (r *OutboxRelay) run(ctx context.Context) error {
+	conn, err := r.Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close(context.Background())
+
+	log.Printf("outbox relay started")
+
+	for {
+		delivered, err := r.RelayBatch(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		// keep going straight away while there is a backlog
+		if delivered == r.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.PollInterval):
+		}
+	}
+}
+
+// RelayBatch tries to deliver a single batch of events, returning how many were
+// attempted. The batch is claimed up front and each event is marked as soon as it
+// is sent, so nothing is held open while waiting on the sink and a failure part
+// way through doesn't undo the events that were already delivered
+func (r *OutboxRelay) RelayBatch(ctx context.Context, db DBTX) (int, error) {
+	events, err := r.claim(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		deliverErr := r.Sink.Deliver(ctx, event)
+		if deliverErr == nil {
+			_, err := db.Exec(ctx, markOutboxDeliveredSQL, event.Id)
+			if err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		var deadLettered bool
+
+		delay := r.retryDelay(event.Attempts)
+
+		err := db.
+			QueryRow(ctx, markOutboxFailedSQL, event.Id, deliverErr.Error(), delay.Seconds(), r.MaxAttempts).
+			Scan(&deadLettered)
+		if err != nil {
+			return 0, err
+		}
+
+		if deadLettered {
+			log.Printf("outbox event dead lettered: [id=%v] [type=%v] [attempts=%v]: %v", event.Id, event.Type, event.Attempts+1, deliverErr)
+		} else {
+			log.Printf("outbox event failed, retrying in %v: [id=%v] [type=%v]: %v", delay, event.Id, event.Type, deliverErr)
+		}
+	}
+
+	return len(events), nil
+}
+
+func (r *OutboxRelay) claim(ctx context.Context, db DBTX) ([]OutboxEvent, error) {
+	rows, err := db.Query(ctx, claimOutboxEventsSQL, r.BatchSize, r.ClaimFor.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var events []OutboxEvent
+
+	for rows.Next() {
+		var e OutboxEvent
+		if err := rows.Scan(&e.Id, &e.CreatedAt, &e.Type, &e.Payload, &e.Attempts); err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE doesn't return rows in any order, they are sent oldest first
+	sort.Slice(events, func(i, j int) bool { return events[i].Id < events[j].Id })
+
+	return events, nil
+}
+
+func (r *OutboxRelay) retryDelay(attempts int) time.Duration {
+	delay := float64(r.RetryDelay) * math.Pow(2, float64(attempts))
+	return time.Duration(math.Min(delay, float64(r.MaxDelay)))
+}
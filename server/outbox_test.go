@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// recordingSink remembers every event it was given and fails the ones in fail
+type recordingSink struct {
+	fail      map[int64]bool
+	delivered []int64
+}
+
+func (s *recordingSink) Deliver(ctx context.Context, event OutboxEvent) error {
+	s.delivered = append(s.delivered, event.Id)
+
+	if s.fail[event.Id] {
+		return errors.New("sink is down")
+	}
+
+	return nil
+}
+
+func TestRelayBatch(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	event := func(id int64, attempts int) []any {
+		return []any{id, now, OutboxLikeRecorded, []byte(`{}`), attempts}
+	}
+
+	tests := []struct {
+		name      string
+		claimed   [][]any
+		fail      map[int64]bool
+		sent      []int64
+		delivered []int64
+		failed    []int64
+	}{
+		{name: "nothing waiting", claimed: nil},
+		{
+			name:      "sent oldest first",
+			claimed:   [][]any{event(3, 0), event(1, 0), event(2, 0)},
+			sent:      []int64{1, 2, 3},
+			delivered: []int64{1, 2, 3},
+		},
+		{
+			name:      "failure in the middle",
+			claimed:   [][]any{event(1, 0), event(2, 3), event(3, 0)},
+			fail:      map[int64]bool{2: true},
+			sent:      []int64{1, 2, 3},
+			delivered: []int64{1, 3},
+			failed:    []int64{2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{
+				results: map[string][][]any{
+					claimOutboxEventsSQL: test.claimed,
+					markOutboxFailedSQL:  {{false}},
+				},
+			}
+
+			sink := &recordingSink{fail: test.fail}
+			relay := NewOutboxRelay(sink, nil)
+
+			attempted, err := relay.RelayBatch(context.Background(), db)
+			if err != nil {
+				t.Fatalf("RelayBatch() error = %v", err)
+			}
+
+			if attempted != len(test.claimed) {
+				t.Errorf("RelayBatch() = %v, want %v", attempted, len(test.claimed))
+			}
+
+			claims := db.ran(claimOutboxEventsSQL)
+			if len(claims) != 1 || !reflect.DeepEqual(claims[0].args, []any{relay.BatchSize, relay.ClaimFor.Seconds()}) {
+				t.Errorf("claim ran %v, want once for a batch", claims)
+			}
+
+			if !reflect.DeepEqual(sink.delivered, test.sent) {
+				t.Errorf("sent %v, want %v", sink.delivered, test.sent)
+			}
+
+			var delivered []int64
+			for _, query := range db.ran(markOutboxDeliveredSQL) {
+				delivered = append(delivered, query.args[0].(int64))
+			}
+
+			if !reflect.DeepEqual(delivered, test.delivered) {
+				t.Errorf("marked delivered %v, want %v", delivered, test.delivered)
+			}
+
+			var failed []int64
+			for _, query := range db.ran(markOutboxFailedSQL) {
+				failed = append(failed, query.args[0].(int64))
+
+				// the backoff comes from how many times it had already failed
+				if query.args[2] != relay.retryDelay(3).Seconds() {
+					t.Errorf("retry delay = %v, want %v", query.args[2], relay.retryDelay(3).Seconds())
+				}
+			}
+
+			if !reflect.DeepEqual(failed, test.failed) {
+				t.Errorf("marked failed %v, want %v", failed, test.failed)
+			}
+
+			// each event is marked on its own so there is no transaction to roll back
+			// the ones that were already sent
+			if db.commits != 0 || db.rollbacks != 0 {
+				t.Errorf("batch ran in a transaction")
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+-- a deleted subscription is never sent to again so anything still waiting for it
+-- is marked failed, otherwise it would sit there pending forever
+UPDATE webhook_deliveries
+SET failed_at = NOW(), last_error = 'subscription was deleted'
+WHERE webhook_deliveries.subscription_id = $1
+AND webhook_deliveries.delivered_at IS NULL
+AND webhook_deliveries.failed_at IS NULL
@@ -0,0 +1,4 @@
+INSERT INTO like_counts (user_id, count)
+VALUES ($1, $2)
+ON CONFLICT (user_id)
+DO UPDATE SET count = like_counts.count + EXCLUDED.count
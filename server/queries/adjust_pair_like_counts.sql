@@ -0,0 +1,18 @@
+-- adds $3 to the count of each user that has a like from the other, used when a
+-- block between them starts or stops hiding those likes. Likes already hidden by
+-- an unmatch were never counted so they are left out
+INSERT INTO like_counts (user_id, count)
+SELECT decisions.to_user, $3
+FROM decisions
+WHERE ((decisions.from_user = $1 AND decisions.to_user = $2) OR (decisions.from_user = $2 AND decisions.to_user = $1))
+AND decisions.liked = true
+-- an unmatch hides the likes made before it, liking again after it shows them again
+AND NOT EXISTS (
+    SELECT 1
+    FROM unmatches
+    WHERE ((unmatches.actor = decisions.to_user AND unmatches.recipient = decisions.from_user)
+    OR (unmatches.actor = decisions.from_user AND unmatches.recipient = decisions.to_user))
+    AND unmatches.created_at >= decisions.decided_at
+)
+ON CONFLICT (user_id)
+DO UPDATE SET count = like_counts.count + EXCLUDED.count
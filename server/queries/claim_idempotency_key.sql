@@ -0,0 +1,13 @@
+-- an expired key is treated as if it was never used, if nothing is returned the
+-- key is still held by an earlier request
+INSERT INTO idempotency_keys (actor_user_id, key, expires_at, recipient_user_id, liked)
+VALUES ($1, $2, NOW() + make_interval(secs => $3::DOUBLE PRECISION), $4, $5)
+ON CONFLICT (actor_user_id, key)
+DO UPDATE SET
+    created_at = NOW(),
+    expires_at = EXCLUDED.expires_at,
+    recipient_user_id = EXCLUDED.recipient_user_id,
+    liked = EXCLUDED.liked,
+    mutual_likes = NULL
+WHERE idempotency_keys.expires_at <= NOW()
+RETURNING true
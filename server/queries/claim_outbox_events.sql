@@ -0,0 +1,17 @@
+-- claims a batch by pushing next_attempt_at out by $2 seconds so no other relay picks
+-- it up in the meantime, this way nothing has to stay locked while events are sent.
+-- If the relay dies part way through the rest are picked up again once that passes.
+-- SKIP LOCKED lets more than one relay claim at once without them getting the same event
+WITH pending AS (
+    SELECT id
+    FROM outbox
+    WHERE outbox.delivered_at IS NULL AND outbox.dead_lettered_at IS NULL AND outbox.next_attempt_at <= NOW()
+    ORDER BY id ASC
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE outbox
+SET next_attempt_at = NOW() + make_interval(secs => $2::DOUBLE PRECISION)
+FROM pending
+WHERE outbox.id = pending.id
+RETURNING outbox.id, outbox.created_at, outbox.event_type, outbox.payload, outbox.attempts
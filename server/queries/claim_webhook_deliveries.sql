@@ -0,0 +1,27 @@
+-- claims a batch the same way claim_outbox_events.sql does, by pushing next_attempt_at
+-- out by $2 seconds so nothing stays locked while the requests are sent
+WITH pending AS (
+    SELECT webhook_deliveries.id
+    FROM webhook_deliveries
+    INNER JOIN webhook_subscriptions ON webhook_deliveries.subscription_id = webhook_subscriptions.id
+    WHERE webhook_deliveries.delivered_at IS NULL
+    AND webhook_deliveries.failed_at IS NULL
+    AND webhook_deliveries.next_attempt_at <= NOW()
+    AND webhook_subscriptions.active = true
+    ORDER BY webhook_deliveries.id ASC
+    LIMIT $1
+    FOR UPDATE OF webhook_deliveries SKIP LOCKED
+)
+UPDATE webhook_deliveries
+SET next_attempt_at = NOW() + make_interval(secs => $2::DOUBLE PRECISION)
+FROM pending, webhook_subscriptions
+WHERE webhook_deliveries.id = pending.id
+AND webhook_subscriptions.id = webhook_deliveries.subscription_id
+RETURNING
+    webhook_deliveries.id,
+    webhook_deliveries.created_at,
+    webhook_deliveries.event_type,
+    webhook_deliveries.payload,
+    webhook_deliveries.attempts,
+    webhook_subscriptions.url,
+    webhook_subscriptions.secret
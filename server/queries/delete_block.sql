@@ -0,0 +1,2 @@
+DELETE FROM blocks
+WHERE blocks.blocker = $1 AND blocks.blocked = $2
\ No newline at end of file
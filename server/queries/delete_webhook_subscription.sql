@@ -0,0 +1,4 @@
+-- subscriptions are never deleted so their delivery logs stay around
+UPDATE webhook_subscriptions
+SET active = false
+WHERE id = $1 AND active = true
\ No newline at end of file
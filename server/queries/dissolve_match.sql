@@ -0,0 +1,10 @@
+-- a match is two likes in opposite directions, turning the like from $1 into
+-- a pass is enough to break it and keeps the decision from $2 untouched
+UPDATE decisions
+SET liked = false, decided_at = NOW()
+WHERE decisions.from_user = $1 AND decisions.to_user = $2 AND decisions.liked = true
+AND EXISTS (
+    SELECT 1
+    FROM decisions AS opposite
+    WHERE opposite.from_user = $2 AND opposite.to_user = $1 AND opposite.liked = true
+)
\ No newline at end of file
@@ -0,0 +1,4 @@
+INSERT INTO webhook_deliveries (subscription_id, event_type, payload)
+SELECT webhook_subscriptions.id, $1, $2::JSONB
+FROM webhook_subscriptions
+WHERE webhook_subscriptions.active = true AND $1 = ANY(webhook_subscriptions.event_types)
\ No newline at end of file
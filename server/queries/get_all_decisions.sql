@@ -0,0 +1,4 @@
+-- decided_at is when the version of the decision that is kept was made
+SELECT id, created_at, from_user, to_user, liked
+FROM decisions
+ORDER BY decided_at ASC, id ASC
\ No newline at end of file
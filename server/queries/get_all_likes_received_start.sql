@@ -1,6 +1,20 @@
-SELECT users.*
+SELECT users.id, users.created_at
 FROM decisions
 INNER JOIN users ON decisions.from_user = users.id
 WHERE decisions.to_user = $1 AND decisions.liked = true
+AND NOT EXISTS (
+    SELECT 1
+    FROM blocks
+    WHERE (blocks.blocker = decisions.to_user AND blocks.blocked = decisions.from_user)
+    OR (blocks.blocker = decisions.from_user AND blocks.blocked = decisions.to_user)
+)
+-- an unmatch hides the likes made before it, liking again after it shows them again
+AND NOT EXISTS (
+    SELECT 1
+    FROM unmatches
+    WHERE ((unmatches.actor = decisions.to_user AND unmatches.recipient = decisions.from_user)
+    OR (unmatches.actor = decisions.from_user AND unmatches.recipient = decisions.to_user))
+    AND unmatches.created_at >= decisions.decided_at
+)
 ORDER BY id DESC
 LIMIT $2
\ No newline at end of file
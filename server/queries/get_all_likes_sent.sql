@@ -1,4 +1,10 @@
-SELECT users.*
+SELECT users.id, users.created_at
 FROM decisions
 INNER JOIN users ON decisions.to_user = users.id
-WHERE decisions.from_user = $1 AND decisions.liked = true
\ No newline at end of file
+WHERE decisions.from_user = $1 AND decisions.liked = true
+AND NOT EXISTS (
+    SELECT 1
+    FROM blocks
+    WHERE (blocks.blocker = decisions.to_user AND blocks.blocked = decisions.from_user)
+    OR (blocks.blocker = decisions.from_user AND blocks.blocked = decisions.to_user)
+)
\ No newline at end of file
@@ -0,0 +1,3 @@
+SELECT id, created_at, tier, desirability
+FROM users
+ORDER BY id ASC
\ No newline at end of file
@@ -0,0 +1,6 @@
+SELECT EXISTS (
+    SELECT 1
+    FROM blocks
+    WHERE (blocks.blocker = $1 AND blocks.blocked = $2)
+    OR (blocks.blocker = $2 AND blocks.blocked = $1)
+)
\ No newline at end of file
@@ -0,0 +1,52 @@
+-- the score is filled in from the ranking being used, see ranking.go. It can use any of
+-- the columns of candidates
+SELECT ranked.id, ranked.created_at, ranked.score
+FROM (
+    SELECT candidates.id, candidates.created_at, %v AS score
+    FROM (
+        SELECT
+            users.id,
+            users.created_at,
+            users.desirability,
+            (
+                SELECT COUNT(*)
+                FROM decisions
+                WHERE decisions.to_user = users.id AND decisions.liked = true
+                AND NOT EXISTS (
+                    SELECT 1
+                    FROM blocks
+                    WHERE (blocks.blocker = decisions.to_user AND blocks.blocked = decisions.from_user)
+                    OR (blocks.blocker = decisions.from_user AND blocks.blocked = decisions.to_user)
+                )
+            ) AS likes_received,
+            -- only users who can see who liked them ($2) are told, otherwise the
+            -- order would give it away
+            $2::BOOLEAN AND EXISTS (
+                SELECT 1
+                FROM decisions
+                WHERE decisions.from_user = users.id AND decisions.to_user = $1 AND decisions.liked = true
+            ) AS liked_you
+        FROM users
+        WHERE users.id != $1
+        AND NOT EXISTS (
+            SELECT 1
+            FROM decisions
+            WHERE decisions.from_user = $1 AND decisions.to_user = users.id
+        )
+        AND NOT EXISTS (
+            SELECT 1
+            FROM decisions
+            WHERE decisions.from_user = users.id AND decisions.to_user = $1 AND decisions.liked = false
+        )
+        AND NOT EXISTS (
+            SELECT 1
+            FROM blocks
+            WHERE (blocks.blocker = $1 AND blocks.blocked = users.id)
+            OR (blocks.blocker = users.id AND blocks.blocked = $1)
+        )
+    ) AS candidates
+) AS ranked
+-- carry on from the last candidate of the previous page ($3 and $4), if there was one
+WHERE $3::DOUBLE PRECISION IS NULL OR ranked.score < $3 OR (ranked.score = $3 AND ranked.id > $4::UUID)
+ORDER BY ranked.score DESC, ranked.id
+LIMIT $5
\ No newline at end of file
@@ -1,3 +1,3 @@
-SELECT *
+SELECT id, created_at, from_user, to_user, liked
 FROM decisions
 WHERE decisions.from_user = $1 AND decisions.to_user = $2 AND decisions.liked = true
\ No newline at end of file
@@ -0,0 +1,15 @@
+SELECT
+    users.id,
+    users.desirability,
+    (
+        SELECT COUNT(*)
+        FROM decisions
+        WHERE decisions.to_user = users.id AND decisions.liked = true
+    ) AS likes_received,
+    (
+        SELECT COUNT(*)
+        FROM decisions
+        WHERE decisions.to_user = users.id AND decisions.liked = false
+    ) AS passes_received
+FROM users
+ORDER BY users.desirability DESC, users.id ASC
\ No newline at end of file
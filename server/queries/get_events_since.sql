@@ -0,0 +1,31 @@
+-- rebuilds the events that would have been sent since $1 from the tables. Likes
+-- are found by when they were last decided so a pass that was changed to a like,
+-- and any match that made, is sent again as well
+SELECT 'like' AS type, decisions.from_user, decisions.to_user, decisions.decided_at AS created_at
+FROM decisions
+WHERE decisions.liked = true AND decisions.decided_at > $1
+AND NOT EXISTS (
+    SELECT 1
+    FROM blocks
+    WHERE (blocks.blocker = decisions.to_user AND blocks.blocked = decisions.from_user)
+    OR (blocks.blocker = decisions.from_user AND blocks.blocked = decisions.to_user)
+)
+UNION ALL
+-- the match was made by whichever of the two likes was decided last
+SELECT 'match' AS type, decisions.from_user, decisions.to_user, decisions.decided_at
+FROM decisions
+INNER JOIN decisions AS opposite
+ON opposite.from_user = decisions.to_user AND opposite.to_user = decisions.from_user AND opposite.liked = true
+AND (opposite.decided_at, opposite.id) < (decisions.decided_at, decisions.id)
+WHERE decisions.liked = true AND decisions.decided_at > $1
+AND NOT EXISTS (
+    SELECT 1
+    FROM blocks
+    WHERE (blocks.blocker = decisions.to_user AND blocks.blocked = decisions.from_user)
+    OR (blocks.blocker = decisions.from_user AND blocks.blocked = decisions.to_user)
+)
+UNION ALL
+SELECT 'unmatch' AS type, unmatches.actor, unmatches.recipient, unmatches.created_at
+FROM unmatches
+WHERE unmatches.created_at > $1
+ORDER BY created_at ASC
\ No newline at end of file
@@ -0,0 +1,3 @@
+SELECT id, created_at, from_user, to_user, liked, desirability_delta
+FROM decisions
+WHERE decisions.from_user = $1 AND decisions.to_user = $2
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- the user with the most likes and the latest user to like them, so the queries
+-- are explained against the busiest rows rather than an empty one
+SELECT decisions.to_user, (
+    SELECT latest.from_user
+    FROM decisions AS latest
+    WHERE latest.to_user = decisions.to_user AND latest.liked = true
+    ORDER BY latest.id DESC
+    LIMIT 1
+)
+FROM decisions
+WHERE decisions.liked = true
+GROUP BY decisions.to_user
+ORDER BY COUNT(*) DESC
+LIMIT 1
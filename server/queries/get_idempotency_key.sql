@@ -0,0 +1,3 @@
+SELECT recipient_user_id, liked, mutual_likes
+FROM idempotency_keys
+WHERE actor_user_id = $1 AND key = $2
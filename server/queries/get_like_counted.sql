@@ -0,0 +1,21 @@
+-- true if there is a like from $1 to $2 that is counted in $2's like count, which
+-- is every like get_all_likes_received.sql lists
+SELECT EXISTS (
+    SELECT 1
+    FROM decisions
+    WHERE decisions.from_user = $1 AND decisions.to_user = $2 AND decisions.liked = true
+    AND NOT EXISTS (
+        SELECT 1
+        FROM blocks
+        WHERE (blocks.blocker = decisions.to_user AND blocks.blocked = decisions.from_user)
+        OR (blocks.blocker = decisions.from_user AND blocks.blocked = decisions.to_user)
+    )
+    -- an unmatch hides the likes made before it, liking again after it shows them again
+    AND NOT EXISTS (
+        SELECT 1
+        FROM unmatches
+        WHERE ((unmatches.actor = decisions.to_user AND unmatches.recipient = decisions.from_user)
+        OR (unmatches.actor = decisions.from_user AND unmatches.recipient = decisions.to_user))
+        AND unmatches.created_at >= decisions.decided_at
+    )
+)
\ No newline at end of file
@@ -1,3 +1,5 @@
-SELECT COUNT(*)
-FROM decisions
-WHERE decisions.to_user = $1 AND decisions.liked = true
\ No newline at end of file
+SELECT COALESCE((
+    SELECT like_counts.count
+    FROM like_counts
+    WHERE like_counts.user_id = $1
+), 0)
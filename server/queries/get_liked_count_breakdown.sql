@@ -0,0 +1,24 @@
+-- replies is the recipient's own decision on each liker, if there is one
+SELECT
+    COUNT(*),
+    COUNT(*) FILTER (WHERE replies.liked IS NULL),
+    COUNT(*) FILTER (WHERE replies.liked = false),
+    COUNT(*) FILTER (WHERE replies.liked = true),
+    COUNT(*) FILTER (WHERE decisions.decided_at > $2::TIMESTAMP)
+FROM decisions
+LEFT JOIN decisions replies ON replies.from_user = decisions.to_user AND replies.to_user = decisions.from_user
+WHERE decisions.to_user = $1 AND decisions.liked = true
+AND NOT EXISTS (
+    SELECT 1
+    FROM blocks
+    WHERE (blocks.blocker = decisions.to_user AND blocks.blocked = decisions.from_user)
+    OR (blocks.blocker = decisions.from_user AND blocks.blocked = decisions.to_user)
+)
+-- an unmatch hides the likes made before it, liking again after it shows them again
+AND NOT EXISTS (
+    SELECT 1
+    FROM unmatches
+    WHERE ((unmatches.actor = decisions.to_user AND unmatches.recipient = decisions.from_user)
+    OR (unmatches.actor = decisions.from_user AND unmatches.recipient = decisions.to_user))
+    AND unmatches.created_at >= decisions.decided_at
+)
@@ -0,0 +1,13 @@
+-- a match happened when the second of the two likes was made
+SELECT decisions.to_user, GREATEST(decisions.created_at, opposite.created_at) AS matched_at
+FROM decisions
+INNER JOIN decisions AS opposite
+ON opposite.from_user = decisions.to_user AND opposite.to_user = decisions.from_user AND opposite.liked = true
+WHERE decisions.from_user = $1 AND decisions.liked = true
+AND NOT EXISTS (
+    SELECT 1
+    FROM blocks
+    WHERE (blocks.blocker = decisions.to_user AND blocks.blocked = decisions.from_user)
+    OR (blocks.blocker = decisions.from_user AND blocks.blocked = decisions.to_user)
+)
+ORDER BY matched_at DESC
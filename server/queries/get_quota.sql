@@ -0,0 +1,5 @@
+SELECT users.tier, tier_quotas.daily_likes, COALESCE(like_usage.used, 0)
+FROM users
+INNER JOIN tier_quotas ON users.tier = tier_quotas.tier
+LEFT JOIN like_usage ON like_usage.user_id = users.id AND like_usage.day = CURRENT_DATE
+WHERE users.id = $1
\ No newline at end of file
@@ -0,0 +1,3 @@
+SELECT id, created_at, tier, desirability
+FROM users
+WHERE users.id = $1
\ No newline at end of file
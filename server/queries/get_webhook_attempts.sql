@@ -0,0 +1,4 @@
+SELECT delivery_id, attempted_at, status_code, error, duration_ms
+FROM webhook_delivery_attempts
+WHERE webhook_delivery_attempts.delivery_id = ANY($1)
+ORDER BY id ASC
\ No newline at end of file
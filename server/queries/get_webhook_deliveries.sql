@@ -0,0 +1,5 @@
+SELECT id, created_at, subscription_id, event_type, attempts, last_status_code, last_error, delivered_at, failed_at
+FROM webhook_deliveries
+WHERE ($1::BOOLEAN = false OR webhook_deliveries.failed_at IS NOT NULL)
+ORDER BY id DESC
+LIMIT $2
\ No newline at end of file
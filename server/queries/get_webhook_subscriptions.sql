@@ -0,0 +1,4 @@
+SELECT id, created_at, url, event_types
+FROM webhook_subscriptions
+WHERE webhook_subscriptions.active = true
+ORDER BY id ASC
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO blocks (blocker, blocked)
+VALUES ($1, $2)
+ON CONFLICT (blocker, blocked) DO NOTHING
\ No newline at end of file
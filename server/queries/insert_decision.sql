@@ -1,5 +1,10 @@
+-- created_at is when the first decision was made, decided_at is when it was last
+-- changed so a pass turned into a like counts as a new like. Sending the same
+-- decision again changes nothing so it keeps its decided_at
 INSERT INTO decisions (from_user, to_user, liked)
 VALUES ($1, $2, $3)
 ON CONFLICT (from_user, to_user)
-DO UPDATE SET liked = $3
+DO UPDATE SET
+    liked = $3,
+    decided_at = CASE WHEN decisions.liked = $3 THEN decisions.decided_at ELSE NOW() END
 RETURNING id, created_at, from_user, to_user, liked;
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO outbox (event_type, payload)
+VALUES ($1, $2::JSONB)
+RETURNING id
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO reports (reporter, reported, reason)
+VALUES ($1, $2, $3)
+RETURNING id
\ No newline at end of file
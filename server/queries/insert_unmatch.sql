@@ -0,0 +1,3 @@
+INSERT INTO unmatches (actor, recipient)
+VALUES ($1, $2)
+RETURNING id, created_at, actor, recipient
\ No newline at end of file
@@ -0,0 +1,2 @@
+INSERT INTO webhook_delivery_attempts (delivery_id, status_code, error, duration_ms)
+VALUES ($1, $2, $3, $4)
\ No newline at end of file
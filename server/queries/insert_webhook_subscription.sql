@@ -0,0 +1,3 @@
+INSERT INTO webhook_subscriptions (url, secret, event_types)
+VALUES ($1, $2, $3)
+RETURNING id, created_at, url, event_types
\ No newline at end of file
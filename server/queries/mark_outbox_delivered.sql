@@ -0,0 +1,3 @@
+UPDATE outbox
+SET delivered_at = NOW(), attempts = attempts + 1, last_error = NULL
+WHERE id = $1
\ No newline at end of file
@@ -0,0 +1,8 @@
+UPDATE outbox
+SET
+    attempts = attempts + 1,
+    last_error = $2,
+    next_attempt_at = NOW() + make_interval(secs => $3::DOUBLE PRECISION),
+    dead_lettered_at = CASE WHEN attempts + 1 >= $4::INTEGER THEN NOW() ELSE NULL END
+WHERE id = $1
+RETURNING dead_lettered_at IS NOT NULL
\ No newline at end of file
@@ -0,0 +1,3 @@
+UPDATE webhook_deliveries
+SET delivered_at = NOW(), attempts = attempts + 1, last_status_code = $2, last_error = NULL
+WHERE id = $1
\ No newline at end of file
@@ -0,0 +1,9 @@
+UPDATE webhook_deliveries
+SET
+    attempts = attempts + 1,
+    last_status_code = $2,
+    last_error = $3,
+    next_attempt_at = NOW() + make_interval(secs => $4::DOUBLE PRECISION),
+    failed_at = CASE WHEN attempts + 1 >= $5::INTEGER THEN NOW() ELSE NULL END
+WHERE id = $1
+RETURNING failed_at IS NOT NULL
\ No newline at end of file
@@ -0,0 +1,6 @@
+SELECT pg_notify($1, json_build_object(
+    'type', $2::TEXT,
+    'actor_id', $3::TEXT,
+    'recipient_id', $4::TEXT,
+    'created_at', NOW()
+)::TEXT)
\ No newline at end of file
@@ -0,0 +1,39 @@
+-- actual is the same count the cache is meant to hold worked out from scratch,
+-- every user that doesn't match is fixed and the drift is reported back
+WITH actual AS (
+    SELECT users.id AS user_id, COUNT(decisions.id) AS count
+    FROM users
+    LEFT JOIN decisions ON decisions.to_user = users.id AND decisions.liked = true
+    AND NOT EXISTS (
+        SELECT 1
+        FROM blocks
+        WHERE (blocks.blocker = decisions.to_user AND blocks.blocked = decisions.from_user)
+        OR (blocks.blocker = decisions.from_user AND blocks.blocked = decisions.to_user)
+    )
+    -- an unmatch hides the likes made before it, liking again after it shows them again
+    AND NOT EXISTS (
+        SELECT 1
+        FROM unmatches
+        WHERE ((unmatches.actor = decisions.to_user AND unmatches.recipient = decisions.from_user)
+        OR (unmatches.actor = decisions.from_user AND unmatches.recipient = decisions.to_user))
+        AND unmatches.created_at >= decisions.decided_at
+    )
+    GROUP BY users.id
+), drifted AS (
+    SELECT actual.user_id, actual.count, COALESCE(like_counts.count, 0) AS cached
+    FROM actual
+    LEFT JOIN like_counts ON like_counts.user_id = actual.user_id
+    WHERE actual.count <> COALESCE(like_counts.count, 0)
+), fixed AS (
+    INSERT INTO like_counts (user_id, count)
+    SELECT drifted.user_id, drifted.count
+    FROM drifted
+    ON CONFLICT (user_id)
+    DO UPDATE SET count = EXCLUDED.count
+)
+SELECT
+    (SELECT COUNT(*) FROM actual),
+    COUNT(*),
+    COALESCE(SUM(ABS(drifted.count - drifted.cached)), 0)::BIGINT,
+    COALESCE(MAX(ABS(drifted.count - drifted.cached)), 0)
+FROM drifted
@@ -0,0 +1,11 @@
+-- only deliveries that never made it can be replayed, the retries start again from zero.
+-- Deliveries for a deleted subscription stay failed as they would never be sent
+UPDATE webhook_deliveries
+SET attempts = 0, failed_at = NULL, next_attempt_at = NOW()
+WHERE webhook_deliveries.delivered_at IS NULL
+AND (webhook_deliveries.id = ANY($1) OR ($2::BOOLEAN AND webhook_deliveries.failed_at IS NOT NULL))
+AND EXISTS (
+    SELECT 1
+    FROM webhook_subscriptions
+    WHERE webhook_subscriptions.id = webhook_deliveries.subscription_id AND webhook_subscriptions.active = true
+)
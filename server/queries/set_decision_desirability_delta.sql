@@ -0,0 +1,5 @@
+-- desirability_delta is how much the decision currently adds to to_user's score, so
+-- it can be taken back again when the decision changes
+UPDATE decisions
+SET desirability_delta = $3
+WHERE decisions.from_user = $1 AND decisions.to_user = $2
\ No newline at end of file
@@ -0,0 +1,3 @@
+UPDATE idempotency_keys
+SET mutual_likes = $3
+WHERE actor_user_id = $1 AND key = $2
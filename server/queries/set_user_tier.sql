@@ -0,0 +1 @@
+UPDATE users SET tier = $2 WHERE id = $1
\ No newline at end of file
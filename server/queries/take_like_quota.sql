@@ -0,0 +1,14 @@
+INSERT INTO like_usage (user_id, day, used)
+SELECT users.id, CURRENT_DATE, 1
+FROM users
+INNER JOIN tier_quotas ON users.tier = tier_quotas.tier
+WHERE users.id = $1 AND tier_quotas.daily_likes > 0
+ON CONFLICT (user_id, day)
+DO UPDATE SET used = like_usage.used + 1
+WHERE like_usage.used < (
+    SELECT tier_quotas.daily_likes
+    FROM users
+    INNER JOIN tier_quotas ON users.tier = tier_quotas.tier
+    WHERE users.id = $1
+)
+RETURNING used;
\ No newline at end of file
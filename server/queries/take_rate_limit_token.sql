@@ -0,0 +1,13 @@
+-- $4 is how many tokens are taken, nothing is taken unless there are that many
+INSERT INTO rate_limits (key, tokens, allowed, updated_at)
+VALUES ($1, $3::DOUBLE PRECISION - $4::DOUBLE PRECISION, true, NOW())
+ON CONFLICT (key)
+DO UPDATE SET
+    tokens = CASE
+        WHEN LEAST($3::DOUBLE PRECISION, rate_limits.tokens + EXTRACT(EPOCH FROM NOW() - rate_limits.updated_at)::DOUBLE PRECISION * $2::DOUBLE PRECISION) >= $4::DOUBLE PRECISION
+        THEN LEAST($3::DOUBLE PRECISION, rate_limits.tokens + EXTRACT(EPOCH FROM NOW() - rate_limits.updated_at)::DOUBLE PRECISION * $2::DOUBLE PRECISION) - $4::DOUBLE PRECISION
+        ELSE LEAST($3::DOUBLE PRECISION, rate_limits.tokens + EXTRACT(EPOCH FROM NOW() - rate_limits.updated_at)::DOUBLE PRECISION * $2::DOUBLE PRECISION)
+    END,
+    allowed = LEAST($3::DOUBLE PRECISION, rate_limits.tokens + EXTRACT(EPOCH FROM NOW() - rate_limits.updated_at)::DOUBLE PRECISION * $2::DOUBLE PRECISION) >= $4::DOUBLE PRECISION,
+    updated_at = NOW()
+RETURNING allowed, tokens;
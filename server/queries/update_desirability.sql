@@ -0,0 +1,4 @@
+UPDATE users
+SET desirability = desirability + $2
+WHERE id = $1
+RETURNING desirability
\ No newline at end of file
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"time"
+)
+
+// Quota is how many likes a user can send in a day, this comes from the tier
+// of the user and the allowance for that tier in the tier_quotas table
+type Quota struct {
+	Tier       string
+	DailyLikes int
+	LikesUsed  int
+}
+
+func (q Quota) Remaining() int {
+	return max(q.DailyLikes-q.LikesUsed, 0)
+}
+
+// ResetsAt is the start of the next day, the usage is stored against CURRENT_DATE
+// which is UTC as that is what the database container runs in
+func (q Quota) ResetsAt() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+func (q Quota) ToResponse() *explore.GetQuotaResponse {
+	return &explore.GetQuotaResponse{
+		Tier:               q.Tier,
+		DailyLikes:         uint64(q.DailyLikes),
+		LikesUsed:          uint64(q.LikesUsed),
+		LikesRemaining:     uint64(q.Remaining()),
+		ResetUnixTimestamp: uint64(q.ResetsAt().Unix()),
+	}
+}
+
+// QuotaExceededError builds the error returned when a user is out of likes. The
+// remaining count and reset time are sent back as trailers so the client doesn't
+// need to call GetQuota to find out when they can like again
+func QuotaExceededError(ctx context.Context, quota Quota) error {
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(
+		"quota-remaining", strconv.Itoa(quota.Remaining()),
+		"quota-reset", strconv.FormatInt(quota.ResetsAt().Unix(), 10),
+	))
+
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("daily like quota of %v used for %v tier", quota.DailyLikes, quota.Tier))
+
+	detailed, err := st.WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{
+			{
+				Subject:     "likes",
+				Description: fmt.Sprintf("%v of %v daily likes used", quota.LikesUsed, quota.DailyLikes),
+			},
+		},
+	})
+	if err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
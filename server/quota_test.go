@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestQuotaRemaining(t *testing.T) {
+	tests := []struct {
+		name      string
+		quota     Quota
+		remaining int
+	}{
+		{name: "unused", quota: Quota{DailyLikes: 10}, remaining: 10},
+		{name: "some used", quota: Quota{DailyLikes: 10, LikesUsed: 4}, remaining: 6},
+		{name: "all used", quota: Quota{DailyLikes: 10, LikesUsed: 10}, remaining: 0},
+		// the allowance can be lowered after likes were already used today
+		{name: "over", quota: Quota{DailyLikes: 5, LikesUsed: 8}, remaining: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if remaining := test.quota.Remaining(); remaining != test.remaining {
+				t.Errorf("Remaining() = %v, want %v", remaining, test.remaining)
+			}
+		})
+	}
+}
+
+func TestQuotaResetsAt(t *testing.T) {
+	resetsAt := Quota{}.ResetsAt()
+	now := time.Now().UTC()
+
+	if resetsAt.Location() != time.UTC || resetsAt.Hour() != 0 || resetsAt.Minute() != 0 || resetsAt.Second() != 0 {
+		t.Errorf("ResetsAt() = %v, want midnight utc", resetsAt)
+	}
+
+	if !resetsAt.After(now) || resetsAt.Sub(now) > 24*time.Hour {
+		t.Errorf("ResetsAt() = %v, want the next midnight after %v", resetsAt, now)
+	}
+}
+
+func TestQuotaExceededError(t *testing.T) {
+	err := QuotaExceededError(context.Background(), Quota{Tier: "free", DailyLikes: 10, LikesUsed: 10})
+
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("code = %v, want ResourceExhausted", st.Code())
+	}
+
+	// the client tells this apart from being rate limited by the details, a
+	// quota failure and never a retry delay
+	var failure *errdetails.QuotaFailure
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.QuotaFailure:
+			failure = detail
+		case *errdetails.RetryInfo:
+			t.Errorf("quota error has a retry delay so looks rate limited")
+		}
+	}
+
+	if failure == nil || len(failure.Violations) != 1 || failure.Violations[0].Subject != "likes" {
+		t.Errorf("details = %v, want a quota failure for likes", st.Details())
+	}
+}
+
+func TestApplyDecisionQuota(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	from := UUID("from")
+	to := UUID("to")
+
+	tests := []struct {
+		name   string
+		liked  bool
+		before *bool
+		// taken is whether there was any quota left to take
+		taken  bool
+		exists bool
+		code   codes.Code
+	}{
+		{name: "like with quota left", liked: true, taken: true, exists: true, code: codes.OK},
+		{name: "like over quota", liked: true, taken: false, exists: true, code: codes.ResourceExhausted},
+		{name: "like from nobody", liked: true, taken: false, exists: false, code: codes.NotFound},
+		{name: "pass over quota", liked: false, taken: false, exists: true, code: codes.OK},
+		{name: "same like again over quota", liked: true, before: ptr(true), taken: false, exists: true, code: codes.OK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{
+				results: map[string][][]any{
+					insertDecisionSQL:    {{1, now, from, to, test.liked}},
+					getBlockExistsSQL:    {{false}},
+					getLikeCountedSQL:    {{true}},
+					insertOutboxEventSQL: {{int64(1)}},
+				},
+			}
+
+			if test.taken {
+				db.results[takeLikeQuotaSQL] = [][]any{{1}}
+			}
+
+			if test.exists {
+				db.results[getQuotaSQL] = [][]any{{"free", 10, 10}}
+			}
+
+			if test.before != nil {
+				db.results[getExistingDecisionSQL] = [][]any{{1, now, from, to, *test.before, 0.0}}
+			}
+
+			server := ExploreServer{Events: NewLocalBroker()}
+
+			request := &explore.PutDecisionRequest{
+				ActorUserId:     string(from),
+				RecipientUserId: string(to),
+				LikedRecipient:  test.liked,
+			}
+
+			_, err := server.applyDecision(context.Background(), db, request)
+			if code := status.Code(err); code != test.code {
+				t.Fatalf("applyDecision() = %v, want %v", err, test.code)
+			}
+
+			// only a new like uses up quota
+			takes := len(db.ran(takeLikeQuotaSQL))
+			if want := boolToInt(test.liked && test.before == nil); takes != want {
+				t.Errorf("quota taken %v times, want %v", takes, want)
+			}
+
+			if test.code != codes.OK && len(db.ran(insertDecisionSQL)) != 0 {
+				t.Errorf("decision was recorded without quota")
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+	"strconv"
+	"strings"
+)
+
+// Ranker gives each candidate a score, candidates with a higher score are shown
+// first. Ties are broken by user id so the order is always the same. The score
+// is worked out by the database so only one page of candidates is ever loaded,
+// Score is the sql for it and can use the columns of candidates in
+// get_candidates.sql (created_at, desirability, likes_received and liked_you)
+type Ranker interface {
+	Name() string
+	Score() string
+}
+
+// RecencyRanker shows the newest users first
+type RecencyRanker struct{}
+
+func (r RecencyRanker) Name() string {
+	return "recency"
+}
+
+func (r RecencyRanker) Score() string {
+	return "EXTRACT(EPOCH FROM candidates.created_at)::DOUBLE PRECISION"
+}
+
+// PopularityRanker shows the users who have received the most likes first
+type PopularityRanker struct{}
+
+func (r PopularityRanker) Name() string {
+	return "popularity"
+}
+
+func (r PopularityRanker) Score() string {
+	return "candidates.likes_received::DOUBLE PRECISION"
+}
+
+// ReciprocityRanker shows the users who are most likely to like you back first.
+// Anyone who has already liked you is a guaranteed match so they are always put
+// ahead of everyone else, after that it falls back to popularity. Users who can't
+// see who liked them never have liked_you set so for them this is just popularity
+type ReciprocityRanker struct{}
+
+const reciprocityBoost float64 = 1_000_000
+
+func (r ReciprocityRanker) Name() string {
+	return "reciprocity"
+}
+
+func (r ReciprocityRanker) Score() string {
+	return fmt.Sprintf("candidates.likes_received + CASE WHEN candidates.liked_you THEN %.0f ELSE 0 END", reciprocityBoost)
+}
+
+// DesirabilityRanker shows the users with the highest desirability first, see
+// desirability.go for how that is kept up to date
+type DesirabilityRanker struct{}
+
+func (r DesirabilityRanker) Name() string {
+	return "desirability"
+}
+
+func (r DesirabilityRanker) Score() string {
+	return "candidates.desirability"
+}
+
+var Rankers = map[string]Ranker{
+	RecencyRanker{}.Name():      RecencyRanker{},
+	PopularityRanker{}.Name():   PopularityRanker{},
+	ReciprocityRanker{}.Name():  ReciprocityRanker{},
+	DesirabilityRanker{}.Name(): DesirabilityRanker{},
+}
+
+const DefaultRanker = "recency"
+
+func RankerByName(name string) (Ranker, error) {
+	if name == "" {
+		name = DefaultRanker
+	}
+
+	ranker, ok := Rankers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown ranking \"%v\"", name)
+	}
+
+	return ranker, nil
+}
+
+// RankedCandidate is a user that could be shown to someone exploring along with
+// the score the ranking gave them
+type RankedCandidate struct {
+	User
+	Score float64
+}
+
+// CandidatesSQL is the candidates query ordered by the ranker
+func CandidatesSQL(ranker Ranker) string {
+	return fmt.Sprintf(getCandidatesSQL, ranker.Score())
+}
+
+// RankCandidates gets the page of candidates after the given candidate along
+// with the pagination token for the page after it. showLikedYou is if the user
+// is allowed to know who liked them, if not the ranking can't use it
+func RankCandidates(ctx context.Context, db DBTX, user User, ranker Ranker, showLikedYou bool, after *RankedCandidate) ([]RankedCandidate, string, error) {
+	page, err := GetCandidates(ctx, db, user, ranker, showLikedYou, after, PaginationSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextPaginationToken string
+	if len(page) > 0 {
+		nextPaginationToken = rankingToken(ranker, page[len(page)-1])
+	}
+
+	return page, nextPaginationToken, nil
+}
+
+// The pagination token is the ranker, score and id of the last candidate on the
+// previous page, this means the next page carries on from the same place even if
+// candidates were added or removed in between requests
+func rankingToken(ranker Ranker, candidate RankedCandidate) string {
+	return fmt.Sprintf("%v:%v:%v", ranker.Name(), strconv.FormatFloat(candidate.Score, 'g', -1, 64), candidate.Id)
+}
+
+func parseRankingToken(ranker Ranker, token string) (RankedCandidate, error) {
+	parts := strings.SplitN(token, ":", 3)
+	if len(parts) != 3 {
+		return RankedCandidate{}, fmt.Errorf("invalid pagination token \"%v\"", token)
+	}
+
+	if parts[0] != ranker.Name() {
+		return RankedCandidate{}, fmt.Errorf("pagination token is for \"%v\" ranking not \"%v\"", parts[0], ranker.Name())
+	}
+
+	score, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return RankedCandidate{}, fmt.Errorf("invalid pagination token \"%v\"", token)
+	}
+
+	// the id goes straight into the query so a bad one has to be caught here,
+	// otherwise it only fails as a cast error in postgres
+	var id pgtype.UUID
+	if err := id.Scan(parts[2]); err != nil {
+		return RankedCandidate{}, fmt.Errorf("invalid pagination token \"%v\"", token)
+	}
+
+	candidate := RankedCandidate{Score: score}
+	candidate.Id = UUID(parts[2])
+
+	return candidate, nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRankCandidates(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	user := User{Id: "me"}
+
+	tests := []struct {
+		name         string
+		ranker       Ranker
+		showLikedYou bool
+		after        *RankedCandidate
+		rows         [][]any
+		want         []UUID
+		token        string
+	}{
+		{
+			name:   "first page",
+			ranker: PopularityRanker{},
+			rows:   [][]any{{UUID("a"), now, 3.0}, {UUID("b"), now, 1.0}},
+			want:   []UUID{"a", "b"},
+			token:  "popularity:1:b",
+		},
+		{
+			name:         "after a candidate",
+			ranker:       ReciprocityRanker{},
+			showLikedYou: true,
+			after:        &RankedCandidate{User: User{Id: "b"}, Score: 1000002},
+			rows:         [][]any{{UUID("c"), now, 2.5}},
+			want:         []UUID{"c"},
+			token:        "reciprocity:2.5:c",
+		},
+		{
+			name:   "last page",
+			ranker: RecencyRanker{},
+			after:  &RankedCandidate{User: User{Id: "z"}, Score: 1},
+			rows:   nil,
+			token:  "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{rows: test.rows}
+
+			page, token, err := RankCandidates(context.Background(), db, user, test.ranker, test.showLikedYou, test.after)
+			if err != nil {
+				t.Fatalf("RankCandidates() error = %v", err)
+			}
+
+			var ids []UUID
+			for _, candidate := range page {
+				ids = append(ids, candidate.Id)
+			}
+
+			if !reflect.DeepEqual(ids, test.want) {
+				t.Errorf("RankCandidates() = %v, want %v", ids, test.want)
+			}
+
+			if token != test.token {
+				t.Errorf("RankCandidates() token = %q, want %q", token, test.token)
+			}
+
+			if !strings.Contains(db.sql, test.ranker.Score()) {
+				t.Errorf("query isn't ranked by %v", test.ranker.Name())
+			}
+
+			var afterScore *float64
+			var afterId *UUID
+			if test.after != nil {
+				afterScore, afterId = &test.after.Score, &test.after.Id
+			}
+
+			args := []any{user.Id, test.showLikedYou, afterScore, afterId, PaginationSize}
+			if !reflect.DeepEqual(db.args, args) {
+				t.Errorf("query args = %v, want %v", db.args, args)
+			}
+		})
+	}
+}
+
+func TestRankCandidatesError(t *testing.T) {
+	db := &fakeDB{err: errors.New("connection lost")}
+
+	if _, _, err := RankCandidates(context.Background(), db, User{Id: "me"}, RecencyRanker{}, false, nil); err == nil {
+		t.Errorf("RankCandidates() should return the query error")
+	}
+}
+
+func TestRankingToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranker Ranker
+		score  float64
+		id     UUID
+		token  string
+	}{
+		{name: "whole score", ranker: PopularityRanker{}, score: 12, id: "00000000-0000-0000-0000-00000000000a", token: "popularity:12:00000000-0000-0000-0000-00000000000a"},
+		{name: "fractional score", ranker: DesirabilityRanker{}, score: 0.125, id: "00000000-0000-0000-0000-00000000000b", token: "desirability:0.125:00000000-0000-0000-0000-00000000000b"},
+		{name: "negative score", ranker: DesirabilityRanker{}, score: -3.5, id: "00000000-0000-0000-0000-00000000000c", token: "desirability:-3.5:00000000-0000-0000-0000-00000000000c"},
+		{name: "boosted score", ranker: ReciprocityRanker{}, score: 1000004, id: "00000000-0000-0000-0000-00000000000d", token: "reciprocity:1.000004e+06:00000000-0000-0000-0000-00000000000d"},
+		{name: "recency score", ranker: RecencyRanker{}, score: 1718452800.25, id: "00000000-0000-0000-0000-00000000000e", token: "recency:1.71845280025e+09:00000000-0000-0000-0000-00000000000e"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidate := RankedCandidate{User: User{Id: test.id}, Score: test.score}
+
+			token := rankingToken(test.ranker, candidate)
+			if token != test.token {
+				t.Errorf("rankingToken() = %v, want %v", token, test.token)
+			}
+
+			parsed, err := parseRankingToken(test.ranker, token)
+			if err != nil {
+				t.Fatalf("parseRankingToken(%v) error = %v", token, err)
+			}
+
+			if parsed.Id != test.id || parsed.Score != test.score {
+				t.Errorf("parseRankingToken(%v) = %v %v, want %v %v", token, parsed.Id, parsed.Score, test.id, test.score)
+			}
+		})
+	}
+}
+
+func TestParseRankingTokenErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "missing parts", token: "popularity:12"},
+		{name: "other ranker", token: "recency:12:00000000-0000-0000-0000-00000000000a"},
+		{name: "score isn't a number", token: "popularity:lots:00000000-0000-0000-0000-00000000000a"},
+		{name: "old id only token", token: "00000000-0000-0000-0000-00000000000a"},
+		{name: "id isn't a uuid", token: "popularity:12:a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := parseRankingToken(PopularityRanker{}, test.token); err == nil {
+				t.Errorf("parseRankingToken(%q) should fail", test.token)
+			}
+		})
+	}
+}
+
+func TestRankerByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "", want: DefaultRanker},
+		{name: "recency", want: "recency"},
+		{name: "popularity", want: "popularity"},
+		{name: "reciprocity", want: "reciprocity"},
+		{name: "desirability", want: "desirability"},
+		{name: "Recency", wantErr: true},
+		{name: "random", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ranker, err := RankerByName(test.name)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("RankerByName(%q) error = %v, want error %v", test.name, err, test.wantErr)
+			}
+
+			if !test.wantErr && ranker.Name() != test.want {
+				t.Errorf("RankerByName(%q) = %v, want %v", test.name, ranker.Name(), test.want)
+			}
+		})
+	}
+}
+
+func TestCandidatesSQL(t *testing.T) {
+	for name, ranker := range Rankers {
+		t.Run(name, func(t *testing.T) {
+			sql := CandidatesSQL(ranker)
+
+			if strings.Contains(sql, "%!") || strings.Contains(sql, "%v") {
+				t.Errorf("score wasn't filled into the query:\n%v", sql)
+			}
+
+			if !strings.Contains(sql, ranker.Score()) {
+				t.Errorf("query doesn't use the %v score", name)
+			}
+		})
+	}
+
+	// liked_you is what gives away who liked you so it has to be the only
+	// thing the boost depends on
+	if score := (ReciprocityRanker{}).Score(); !strings.Contains(score, "WHEN candidates.liked_you THEN 1000000") {
+		t.Errorf("reciprocity score = %v, want the boost to come from liked_you", score)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"log"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//go:embed queries/take_rate_limit_token.sql
+var takeRateLimitTokenSQL string
+
+// RateLimit is a token bucket, Rate is how many tokens are added every second
+// and Burst is the most tokens the bucket can ever hold
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// DefaultRateLimits are the limits for each RPC, any method not in here is not limited.
+// The burst is big enough for a full PutDecisions batch, see SharedRateLimits
+var DefaultRateLimits = map[string]RateLimit{
+	explore.ExploreService_PutDecision_FullMethodName: {Rate: 5, Burst: MaxBatchDecisions},
+}
+
+// SharedRateLimits are RPCs that take from the bucket of another RPC instead of
+// having their own. A batch takes a token for every decision in it from the same
+// bucket as PutDecision, otherwise batching would get around the limit
+var SharedRateLimits = map[string]string{
+	explore.ExploreService_PutDecisions_FullMethodName: explore.ExploreService_PutDecision_FullMethodName,
+}
+
+type RateLimiter interface {
+	// Take attempts to take cost tokens from the bucket for key. If there aren't
+	// enough false is returned along with how long until there will be
+	Take(ctx context.Context, key string, limit RateLimit, cost int) (bool, time.Duration, error)
+}
+
+func NewRateLimiter(backend string, db *pgxpool.Pool) (RateLimiter, error) {
+	switch backend {
+	case "", "memory":
+		return NewMemoryRateLimiter(), nil
+	case "postgres":
+		return &PostgresRateLimiter{Database: db}, nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend \"%v\"", backend)
+	}
+}
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+
+	// fullAt is when the bucket will have refilled, after that it is the same
+	// as a new bucket so it can be forgotten
+	fullAt time.Time
+}
+
+// rateLimitSweepInterval is how often idle buckets are looked for
+const rateLimitSweepInterval = time.Minute
+
+type MemoryRateLimiter struct {
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{buckets: make(map[string]*tokenBucket)}
+}
+
+func (l *MemoryRateLimiter) Take(ctx context.Context, key string, limit RateLimit, cost int) (bool, time.Duration, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+
+	// every user gets their own bucket so without this it would hold everyone
+	// that has ever made a request
+	if now.Sub(l.lastSweep) > rateLimitSweepInterval {
+		l.sweep(now)
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), updatedAt: now}
+		l.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.updatedAt).Seconds()
+	bucket.tokens = math.Min(float64(limit.Burst), bucket.tokens+elapsed*limit.Rate)
+	bucket.updatedAt = now
+
+	if bucket.tokens < float64(cost) {
+		return false, retryAfter(bucket.tokens, cost, limit), nil
+	}
+
+	bucket.tokens -= float64(cost)
+
+	refill := (float64(limit.Burst) - bucket.tokens) / limit.Rate
+	bucket.fullAt = now.Add(time.Duration(refill * float64(time.Second)))
+
+	return true, 0, nil
+}
+
+// sweep forgets every bucket that has refilled, this has to be called with the lock held
+func (l *MemoryRateLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		if !now.Before(bucket.fullAt) {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.lastSweep = now
+}
+
+// PostgresRateLimiter keeps the buckets in the rate_limits table so that every
+// server instance shares the same limits. The refill and take is done in a
+// single upsert so two instances can't both take the last token
+type PostgresRateLimiter struct {
+	Database *pgxpool.Pool
+}
+
+func (l *PostgresRateLimiter) Take(ctx context.Context, key string, limit RateLimit, cost int) (bool, time.Duration, error) {
+	var allowed bool
+	var tokens float64
+
+	err := l.Database.
+		QueryRow(ctx, takeRateLimitTokenSQL, key, limit.Rate, limit.Burst, cost).
+		Scan(&allowed, &tokens)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if !allowed {
+		return false, retryAfter(tokens, cost, limit), nil
+	}
+
+	return true, 0, nil
+}
+
+func retryAfter(tokens float64, cost int, limit RateLimit) time.Duration {
+	seconds := (float64(cost) - tokens) / limit.Rate
+	return time.Duration(seconds * float64(time.Second))
+}
+
+type actorRequest interface {
+	GetActorUserId() string
+}
+
+type recipientRequest interface {
+	GetRecipientUserId() string
+}
+
+type batchRequest interface {
+	GetDecisions() []*explore.PutDecisionRequest
+}
+
+// CallerId finds which user a request is being made by. There is no auth so this
+// is taken from the request itself, the actor is preferred over the recipient as
+// that is the one making the change. A batch is made by the actor of its first
+// decision, PutDecisions makes sure the rest are by the same actor
+func CallerId(request any) string {
+	if r, ok := request.(batchRequest); ok && len(r.GetDecisions()) > 0 {
+		return r.GetDecisions()[0].ActorUserId
+	}
+
+	if r, ok := request.(actorRequest); ok && r.GetActorUserId() != "" {
+		return r.GetActorUserId()
+	}
+
+	if r, ok := request.(recipientRequest); ok {
+		return r.GetRecipientUserId()
+	}
+
+	return ""
+}
+
+// RequestCost is how many tokens a request takes, a batch costs the same as
+// making each of its decisions on their own
+func RequestCost(request any) int {
+	if r, ok := request.(batchRequest); ok {
+		return max(len(r.GetDecisions()), 1)
+	}
+
+	return 1
+}
+
+func RateLimitInterceptor(limiter RateLimiter, limits map[string]RateLimit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := info.FullMethod
+		if shared, ok := SharedRateLimits[method]; ok {
+			method = shared
+		}
+
+		limit, ok := limits[method]
+		if !ok {
+			return handler(ctx, request)
+		}
+
+		// this could never be allowed no matter how long they waited
+		cost := RequestCost(request)
+		if cost > limit.Burst {
+			return nil, status.Errorf(codes.InvalidArgument, "at most %v decisions can be sent at once", limit.Burst)
+		}
+
+		key := fmt.Sprintf("%v:%v", method, CallerId(request))
+
+		allowed, wait, err := limiter.Take(ctx, key, limit, cost)
+		if err != nil {
+			// don't lock everyone out because the limiter is broken
+			log.Printf("error checking rate limit for %v: %v", key, err)
+			return handler(ctx, request)
+		}
+
+		if !allowed {
+			log.Printf("rate limited: [key=%v] [cost=%v] [retry=%v]", key, cost, wait)
+
+			seconds := int(math.Ceil(wait.Seconds()))
+			_ = grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+
+			st := status.New(codes.ResourceExhausted, fmt.Sprintf("rate limit exceeded, retry after %vs", seconds))
+			if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+				st = detailed
+			}
+
+			return nil, st.Err()
+		}
+
+		return handler(ctx, request)
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+// recordingLimiter answers every Take the same way and remembers what it was asked
+type recordingLimiter struct {
+	allowed bool
+	wait    time.Duration
+	err     error
+
+	calls int
+	key   string
+	cost  int
+}
+
+func (l *recordingLimiter) Take(ctx context.Context, key string, limit RateLimit, cost int) (bool, time.Duration, error) {
+	l.calls += 1
+	l.key, l.cost = key, cost
+
+	return l.allowed, l.wait, l.err
+}
+
+func batchOf(actor string, size int) *explore.PutDecisionsRequest {
+	request := &explore.PutDecisionsRequest{}
+	for range size {
+		request.Decisions = append(request.Decisions, &explore.PutDecisionRequest{ActorUserId: actor, RecipientUserId: "b"})
+	}
+
+	return request
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	single := explore.ExploreService_PutDecision_FullMethodName
+	batch := explore.ExploreService_PutDecisions_FullMethodName
+
+	tests := []struct {
+		name    string
+		method  string
+		request any
+		limiter *recordingLimiter
+		code    codes.Code
+		handled bool
+		key     string
+		cost    int
+	}{
+		{
+			name:    "single decision",
+			method:  single,
+			request: &explore.PutDecisionRequest{ActorUserId: "a", RecipientUserId: "b"},
+			limiter: &recordingLimiter{allowed: true},
+			handled: true,
+			key:     single + ":a",
+			cost:    1,
+		},
+		{
+			name:    "batch takes from the single bucket",
+			method:  batch,
+			request: batchOf("a", 3),
+			limiter: &recordingLimiter{allowed: true},
+			handled: true,
+			key:     single + ":a",
+			cost:    3,
+		},
+		{
+			name:    "full batch",
+			method:  batch,
+			request: batchOf("a", MaxBatchDecisions),
+			limiter: &recordingLimiter{allowed: true},
+			handled: true,
+			key:     single + ":a",
+			cost:    MaxBatchDecisions,
+		},
+		{
+			name:    "batch bigger than the bucket",
+			method:  batch,
+			request: batchOf("a", MaxBatchDecisions+1),
+			limiter: &recordingLimiter{allowed: true},
+			code:    codes.InvalidArgument,
+		},
+		{
+			name:    "limited",
+			method:  single,
+			request: &explore.PutDecisionRequest{ActorUserId: "a"},
+			limiter: &recordingLimiter{allowed: false, wait: 1500 * time.Millisecond},
+			code:    codes.ResourceExhausted,
+			key:     single + ":a",
+			cost:    1,
+		},
+		{
+			name:    "limiter broken",
+			method:  single,
+			request: &explore.PutDecisionRequest{ActorUserId: "a"},
+			limiter: &recordingLimiter{err: errors.New("no database")},
+			handled: true,
+			key:     single + ":a",
+			cost:    1,
+		},
+		{
+			name:    "method not limited",
+			method:  explore.ExploreService_ListMatches_FullMethodName,
+			request: &explore.ListMatchesRequest{UserId: "a"},
+			limiter: &recordingLimiter{},
+			handled: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handled := false
+			handler := func(ctx context.Context, request any) (any, error) {
+				handled = true
+				return nil, nil
+			}
+
+			interceptor := RateLimitInterceptor(test.limiter, DefaultRateLimits)
+
+			_, err := interceptor(context.Background(), test.request, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+			if status.Code(err) != test.code {
+				t.Fatalf("interceptor error = %v, want %v", err, test.code)
+			}
+
+			if handled != test.handled {
+				t.Errorf("handler called = %v, want %v", handled, test.handled)
+			}
+
+			if test.key == "" {
+				if test.limiter.calls != 0 {
+					t.Errorf("limiter was asked for %v", test.limiter.key)
+				}
+				return
+			}
+
+			if test.limiter.key != test.key || test.limiter.cost != test.cost {
+				t.Errorf("Take(%v, %v), want Take(%v, %v)", test.limiter.key, test.limiter.cost, test.key, test.cost)
+			}
+
+			if test.code == codes.ResourceExhausted {
+				retry, ok := status.Convert(err).Details()[0].(*errdetails.RetryInfo)
+				if !ok || retry.RetryDelay.AsDuration() != test.limiter.wait {
+					t.Errorf("details = %v, want the retry delay", status.Convert(err).Details())
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryRateLimiterCost(t *testing.T) {
+	ctx := context.Background()
+	limit := RateLimit{Rate: 1, Burst: 10}
+	limiter := NewMemoryRateLimiter()
+
+	tests := []struct {
+		name    string
+		cost    int
+		allowed bool
+	}{
+		{name: "batch from a full bucket", cost: 8, allowed: true},
+		{name: "batch bigger than what is left", cost: 3, allowed: false},
+		{name: "nothing was taken by the denied batch", cost: 2, allowed: true},
+		{name: "empty", cost: 1, allowed: false},
+	}
+
+	// each step carries on from the last so they can't run on their own
+	for _, test := range tests {
+		allowed, wait, err := limiter.Take(ctx, "a", limit, test.cost)
+		if err != nil {
+			t.Fatalf("%v: Take() error = %v", test.name, err)
+		}
+
+		if allowed != test.allowed {
+			t.Errorf("%v: Take(%v) = %v, want %v", test.name, test.cost, allowed, test.allowed)
+		}
+
+		if !allowed && wait <= 0 {
+			t.Errorf("%v: Take(%v) wait = %v, want how long until there are enough", test.name, test.cost, wait)
+		}
+	}
+}
+
+func TestMemoryRateLimiterRefill(t *testing.T) {
+	ctx := context.Background()
+	limit := RateLimit{Rate: 2, Burst: 5}
+	limiter := NewMemoryRateLimiter()
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		cost    int
+		allowed bool
+	}{
+		{name: "new bucket starts full", cost: 5, allowed: true},
+		{name: "empty", cost: 1, allowed: false},
+		{name: "refilled for a second", elapsed: time.Second, cost: 2, allowed: true},
+		{name: "only what was refilled", cost: 1, allowed: false},
+		{name: "half a token isn't enough", elapsed: 250 * time.Millisecond, cost: 1, allowed: false},
+		{name: "both halves make a token", elapsed: 250 * time.Millisecond, cost: 1, allowed: true},
+		{name: "never refills past the burst", elapsed: time.Hour, cost: 6, allowed: false},
+		{name: "full burst after a long wait", cost: 5, allowed: true},
+	}
+
+	// each step carries on from the last so they can't run on their own
+	for _, test := range tests {
+		// winding the clock back on the bucket is the same as waiting
+		if bucket, ok := limiter.buckets["a"]; ok {
+			bucket.updatedAt = bucket.updatedAt.Add(-test.elapsed)
+		}
+
+		allowed, _, err := limiter.Take(ctx, "a", limit, test.cost)
+		if err != nil {
+			t.Fatalf("%v: Take() error = %v", test.name, err)
+		}
+
+		if allowed != test.allowed {
+			t.Errorf("%v: Take(%v) = %v, want %v", test.name, test.cost, allowed, test.allowed)
+		}
+	}
+}
+
+func TestMemoryRateLimiterSweep(t *testing.T) {
+	ctx := context.Background()
+	limit := RateLimit{Rate: 1, Burst: 5}
+	limiter := NewMemoryRateLimiter()
+
+	for _, key := range []string{"idle", "busy"} {
+		if _, _, err := limiter.Take(ctx, key, limit, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// idle has refilled so is the same as a new bucket, busy still has a while to go
+	limiter.buckets["idle"].fullAt = time.Now().Add(-time.Second)
+	limiter.buckets["busy"].fullAt = time.Now().Add(time.Hour)
+
+	// nothing is swept until the interval has passed
+	if _, _, err := limiter.Take(ctx, "other", limit, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(limiter.buckets) != 3 {
+		t.Fatalf("buckets = %v, want nothing swept yet", len(limiter.buckets))
+	}
+
+	limiter.lastSweep = time.Now().Add(-2 * rateLimitSweepInterval)
+
+	if _, _, err := limiter.Take(ctx, "other", limit, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := limiter.buckets["idle"]; ok {
+		t.Errorf("idle bucket wasn't swept")
+	}
+
+	if _, ok := limiter.buckets["busy"]; !ok {
+		t.Errorf("busy bucket was swept before it refilled")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens float64
+		cost   int
+		limit  RateLimit
+		wait   time.Duration
+	}{
+		{name: "empty bucket", tokens: 0, cost: 1, limit: RateLimit{Rate: 5, Burst: 5}, wait: 200 * time.Millisecond},
+		{name: "part of a token", tokens: 0.5, cost: 1, limit: RateLimit{Rate: 1, Burst: 5}, wait: 500 * time.Millisecond},
+		{name: "batch waits for all of it", tokens: 2, cost: 10, limit: RateLimit{Rate: 4, Burst: 20}, wait: 2 * time.Second},
+		{name: "slow refill", tokens: 0, cost: 1, limit: RateLimit{Rate: 0.1, Burst: 1}, wait: 10 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if wait := retryAfter(test.tokens, test.cost, test.limit); wait != test.wait {
+				t.Errorf("retryAfter(%v, %v, %+v) = %v, want %v", test.tokens, test.cost, test.limit, wait, test.wait)
+			}
+		})
+	}
+}
+
+func TestCallerId(t *testing.T) {
+	tests := []struct {
+		name    string
+		request any
+		caller  string
+		cost    int
+	}{
+		{name: "actor", request: &explore.PutDecisionRequest{ActorUserId: "a", RecipientUserId: "b"}, caller: "a", cost: 1},
+		{name: "no actor", request: &explore.PutDecisionRequest{RecipientUserId: "b"}, caller: "b", cost: 1},
+		{name: "recipient only", request: &explore.ListLikedYouRequest{RecipientUserId: "b"}, caller: "b", cost: 1},
+		{name: "batch", request: batchOf("a", 3), caller: "a", cost: 3},
+		{name: "empty batch", request: &explore.PutDecisionsRequest{}, caller: "", cost: 1},
+		{name: "no user", request: &explore.GetCacheStatsRequest{}, caller: "", cost: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if caller := CallerId(test.request); caller != test.caller {
+				t.Errorf("CallerId() = %q, want %q", caller, test.caller)
+			}
+
+			if cost := RequestCost(test.request); cost != test.cost {
+				t.Errorf("RequestCost() = %v, want %v", cost, test.cost)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"sync"
+	"time"
+)
+
+// ConnectToReplica opens a pool of connections to a read only replica. Nothing
+// is connected until the first query so this doesn't wait for the replica to be up
+func ConnectToReplica(ctx context.Context, host string, user string, password string, database string, port string) (*pgxpool.Pool, error) {
+	url := fmt.Sprintf("postgres://%v:%v@%v:%v/%v?sslmode=disable&default_transaction_read_only=on", user, password, host, port, database)
+
+	return pgxpool.New(ctx, url)
+}
+
+// ReadRouter picks where read only queries go. Without a replica everything goes
+// to the primary. A replica can be behind the primary so a user that has just
+// made a change might not see it there, to stop that any user that writes, or
+// whose likes were changed by someone else, has their reads sent to the primary
+// for StickyFor afterwards. This is only
+// tracked in this process so it doesn't help if the next read goes to another server
+type ReadRouter struct {
+	Primary   DBTX
+	Replica   DBTX
+	StickyFor time.Duration
+
+	mutex     sync.Mutex
+	writes    map[UUID]time.Time
+	lastSweep time.Time
+}
+
+func NewReadRouter(primary DBTX, replica DBTX, stickyFor time.Duration) *ReadRouter {
+	return &ReadRouter{
+		Primary:   primary,
+		Replica:   replica,
+		StickyFor: stickyFor,
+		writes:    make(map[UUID]time.Time),
+	}
+}
+
+// Read is where a read made by the user should go
+func (r *ReadRouter) Read(user UUID) DBTX {
+	if r.Replica == nil {
+		return r.Primary
+	}
+
+	if r.StickyFor <= 0 {
+		return r.Replica
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	wroteAt, ok := r.writes[user]
+	if ok && time.Since(wroteAt) < r.StickyFor {
+		return r.Primary
+	}
+
+	return r.Replica
+}
+
+// Wrote records that the users just made or were affected by a change, this
+// should be called after it has been committed
+func (r *ReadRouter) Wrote(users ...UUID) {
+	if r.Replica == nil || r.StickyFor <= 0 {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+
+	for _, user := range users {
+		r.writes[user] = now
+	}
+
+	// forget anyone whose writes are old enough to be on the replica by now,
+	// otherwise this would hold everyone that has ever written
+	if now.Sub(r.lastSweep) > r.StickyFor {
+		for user, wroteAt := range r.writes {
+			if now.Sub(wroteAt) >= r.StickyFor {
+				delete(r.writes, user)
+			}
+		}
+
+		r.lastSweep = now
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadRouter(t *testing.T) {
+	primary := &fakeDB{}
+	replica := &fakeDB{}
+
+	tests := []struct {
+		name      string
+		replica   DBTX
+		stickyFor time.Duration
+		wrote     []UUID
+		wait      time.Duration
+		reader    UUID
+		want      DBTX
+	}{
+		{name: "no replica", replica: nil, stickyFor: time.Minute, reader: "a", want: primary},
+		{name: "no replica after writing", replica: nil, stickyFor: time.Minute, wrote: []UUID{"a"}, reader: "a", want: primary},
+		{name: "nobody wrote", replica: replica, stickyFor: time.Minute, reader: "a", want: replica},
+		{name: "not sticky", replica: replica, stickyFor: 0, wrote: []UUID{"a"}, reader: "a", want: replica},
+		{name: "just wrote", replica: replica, stickyFor: time.Minute, wrote: []UUID{"a"}, reader: "a", want: primary},
+		{name: "someone else wrote", replica: replica, stickyFor: time.Minute, wrote: []UUID{"b"}, reader: "a", want: replica},
+		{name: "both users of a write", replica: replica, stickyFor: time.Minute, wrote: []UUID{"a", "b"}, reader: "b", want: primary},
+		{name: "wrote a while ago", replica: replica, stickyFor: 10 * time.Millisecond, wrote: []UUID{"a"}, wait: 20 * time.Millisecond, reader: "a", want: replica},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := NewReadRouter(primary, test.replica, test.stickyFor)
+
+			router.Wrote(test.wrote...)
+			time.Sleep(test.wait)
+
+			if got := router.Read(test.reader); got != test.want {
+				t.Errorf("Read(%v) = %v, want %v", test.reader, routedTo(got, primary), routedTo(test.want, primary))
+			}
+		})
+	}
+}
+
+func TestReadRouterForgetsOldWrites(t *testing.T) {
+	router := NewReadRouter(&fakeDB{}, &fakeDB{}, 10*time.Millisecond)
+
+	router.Wrote("a", "b")
+	time.Sleep(20 * time.Millisecond)
+
+	// the next write is what clears out the old ones
+	router.Wrote("c")
+
+	if len(router.writes) != 1 {
+		t.Errorf("writes = %v, want only the latest one kept", router.writes)
+	}
+}
+
+func routedTo(db DBTX, primary DBTX) string {
+	if db == primary {
+		return "primary"
+	}
+
+	return "replica"
+}
@@ -2,16 +2,29 @@ package main
 
 import (
 	"context"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackdelahunt/protoexplore/explore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
+	"time"
 )
 
+// MaxBatchDecisions is the most decisions PutDecisions takes in one request
+const MaxBatchDecisions = 100
+
 type ExploreServer struct {
 	explore.UnimplementedExploreServiceServer
 
-	Port     string
-	Database *pgx.Conn
+	Port        string
+	Database    *pgxpool.Pool
+	RateLimiter RateLimiter
+	Events      EventBroker
+	Likes       *LikesCache
+	Reads       *ReadRouter
+
+	DefaultRanking string
 }
 
 func (s ExploreServer) ListLikedYou(ctx context.Context, request *explore.ListLikedYouRequest) (*explore.ListLikedYouResponse, error) {
@@ -19,16 +32,19 @@ func (s ExploreServer) ListLikedYou(ctx context.Context, request *explore.ListLi
 		Id: UUID(request.RecipientUserId),
 	}
 
+	// nothing is changed so this can go to a replica
+	db := s.Reads.Read(user.Id)
+
 	var users []User
 	var paginationToken string
 	var err error
 
 	if request.PaginationToken == nil {
 		log.Printf("ListLikedYou request: [page=nil] [id=%v]", request.RecipientUserId)
-		users, paginationToken, err = GetAllLikesStart(ctx, s.Database, user)
+		users, paginationToken, err = s.Likes.GetAllLikesStart(ctx, db, user)
 	} else {
 		log.Printf("ListLikedYou request: [page=%v] [id=%v]", *request.PaginationToken, request.RecipientUserId)
-		users, paginationToken, err = GetAllLikesPaged(ctx, s.Database, user, *request.PaginationToken)
+		users, paginationToken, err = GetAllLikesPaged(ctx, db, user, *request.PaginationToken)
 	}
 
 	if err != nil {
@@ -62,6 +78,23 @@ func (s ExploreServer) ListLikedYou(ctx context.Context, request *explore.ListLi
 		NextPaginationToken: paginationTokenPtr,
 	}
 
+	var caller User
+	if _, err := GetUser(ctx, db, user, &caller); err != nil {
+		log.Printf("error getting caller for like list: %v", err)
+		return nil, err
+	}
+
+	visibility := LikerVisibilityForUser(caller)
+	if visibility != LikersFull {
+		count, err := s.Likes.GetLikeCount(ctx, db, user)
+		if err != nil {
+			log.Printf("error getting liked count: %v", err)
+			return nil, err
+		}
+
+		ApplyLikerVisibility(visibility, response, count)
+	}
+
 	return response, nil
 }
 func (s ExploreServer) ListNewLikedYou(ctx context.Context, request *explore.ListLikedYouRequest) (*explore.ListLikedYouResponse, error) {
@@ -71,7 +104,9 @@ func (s ExploreServer) ListNewLikedYou(ctx context.Context, request *explore.Lis
 		Id: UUID(request.RecipientUserId),
 	}
 
-	users, err := GetAllLikesOneWay(ctx, s.Database, user)
+	db := s.Reads.Read(user.Id)
+
+	users, err := GetAllLikesOneWay(ctx, db, user)
 	if err != nil {
 		log.Printf("error getting new like list: %v", err)
 		return nil, err
@@ -91,50 +126,655 @@ func (s ExploreServer) ListNewLikedYou(ctx context.Context, request *explore.Lis
 		NextPaginationToken: nil,
 	}
 
+	var caller User
+	if _, err := GetUser(ctx, db, user, &caller); err != nil {
+		log.Printf("error getting caller for new like list: %v", err)
+		return nil, err
+	}
+
+	// everyone is in the one response so the total is just how many there are
+	ApplyLikerVisibility(LikerVisibilityForUser(caller), response, uint64(len(likers)))
+
+	return response, nil
+}
+func (s ExploreServer) ListMatches(ctx context.Context, request *explore.ListMatchesRequest) (*explore.ListMatchesResponse, error) {
+	log.Printf("ListMatches request: [id=%v]", request.UserId)
+
+	user := User{
+		Id: UUID(request.UserId),
+	}
+
+	matches, err := GetMatches(ctx, s.Reads.Read(user.Id), user)
+	if err != nil {
+		log.Printf("error getting matches: %v", err)
+		return nil, err
+	}
+
+	responseMatches := make([]*explore.MatchEvent, len(matches))
+
+	for i, match := range matches {
+		responseMatches[i] = &explore.MatchEvent{
+			UserId:        string(match.UserId),
+			UnixTimestamp: uint64(match.MatchedAt.Unix()),
+		}
+	}
+
+	response := &explore.ListMatchesResponse{Matches: responseMatches}
+
 	return response, nil
 }
 func (s ExploreServer) CountLikedYou(ctx context.Context, request *explore.CountLikedYouRequest) (*explore.CountLikedYouResponse, error) {
-	log.Printf("CountLikedYou request: [id=%v]", request.RecipientUserId)
+	log.Printf("CountLikedYou request: [id=%v] [breakdown=%v] [since=%v]", request.RecipientUserId, request.IncludeBreakdown, request.GetSinceUnixTimestamp())
 
 	user := User{
 		Id: UUID(request.RecipientUserId),
 	}
 
-	count, err := GetLikeCount(ctx, s.Database, user)
+	db := s.Reads.Read(user.Id)
+
+	// the plain count is cheaper so only do the breakdown if something in it was asked for
+	if !request.IncludeBreakdown && request.SinceUnixTimestamp == nil {
+		count, err := s.Likes.GetLikeCount(ctx, db, user)
+		if err != nil {
+			log.Printf("error getting liked count: %v", err)
+			return nil, err
+		}
+
+		response := &explore.CountLikedYouResponse{Count: count}
+
+		return response, nil
+	}
+
+	var since *time.Time
+	if request.SinceUnixTimestamp != nil {
+		sinceTime := time.Unix(int64(*request.SinceUnixTimestamp), 0)
+		since = &sinceTime
+	}
+
+	breakdown, err := GetLikeCountBreakdown(ctx, db, user, since)
 	if err != nil {
-		log.Printf("error getting liked count: %v", err)
+		log.Printf("error getting liked count breakdown: %v", err)
 		return nil, err
 	}
 
-	response := &explore.CountLikedYouResponse{Count: count}
+	response := &explore.CountLikedYouResponse{
+		Count: breakdown.Total,
+		Breakdown: &explore.CountLikedYouResponse_Breakdown{
+			Total:      breakdown.Total,
+			Unanswered: breakdown.Unanswered,
+			Passed:     breakdown.Passed,
+			Matches:    breakdown.Matches,
+		},
+	}
+
+	if since != nil {
+		response.Breakdown.Since = &breakdown.Since
+	}
 
 	return response, nil
 }
 func (s ExploreServer) PutDecision(ctx context.Context, request *explore.PutDecisionRequest) (*explore.PutDecisionResponse, error) {
 	log.Printf("PutDecision request: [from=%v] [to=%v]", request.ActorUserId, request.RecipientUserId)
 
+	// everything the decision changes, along with the events it publishes, is
+	// committed together so nothing hears about a decision that didn't happen
+	tx, err := BeginTx(ctx, s.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	response, err := s.applyDecision(ctx, tx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.committed(ctx, UUID(request.ActorUserId), User{Id: UUID(request.RecipientUserId)})
+
+	return response, nil
+}
+func (s ExploreServer) PutDecisions(ctx context.Context, request *explore.PutDecisionsRequest) (*explore.PutDecisionsResponse, error) {
+	log.Printf("PutDecisions request: [count=%v] [atomic=%v]", len(request.Decisions), request.Atomic)
+
+	if len(request.Decisions) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one decision is required")
+	}
+
+	if len(request.Decisions) > MaxBatchDecisions {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %v decisions can be sent at once", MaxBatchDecisions)
+	}
+
+	// the batch is rate limited as its actor so it can't be made by anyone else
+	actor := request.Decisions[0].ActorUserId
+	for _, decisionRequest := range request.Decisions {
+		if decisionRequest.ActorUserId != actor {
+			return nil, status.Error(codes.InvalidArgument, "every decision in a batch must have the same actor")
+		}
+	}
+
+	tx, err := BeginTx(ctx, s.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	results := make([]*explore.PutDecisionsResponse_Result, len(request.Decisions))
+	recipients := make([]User, 0, len(request.Decisions))
+
+	for i, decisionRequest := range request.Decisions {
+		if request.Atomic {
+			decisionResponse, err := s.applyDecision(ctx, tx, decisionRequest)
+			if err != nil {
+				log.Printf("PutDecisions failed, rolling back: [index=%v]: %v", i, err)
+				return nil, err
+			}
+
+			results[i] = &explore.PutDecisionsResponse_Result{MutualLikes: decisionResponse.MutualLikes, Replayed: decisionResponse.Replayed}
+			recipients = append(recipients, User{Id: UUID(decisionRequest.RecipientUserId)})
+			continue
+		}
+
+		// every decision gets its own savepoint so one failing only throws
+		// away its own changes and the rest still go through
+		decisionResponse, err := s.applyDecisionInSavepoint(ctx, tx, decisionRequest)
+		if err != nil {
+			log.Printf("PutDecisions decision failed: [index=%v] [from=%v] [to=%v]: %v", i, decisionRequest.ActorUserId, decisionRequest.RecipientUserId, err)
+
+			st := status.Convert(err)
+			code := uint32(st.Code())
+
+			results[i] = &explore.PutDecisionsResponse_Result{
+				ErrorCode:    &code,
+				ErrorMessage: st.Message(),
+			}
+			continue
+		}
+
+		results[i] = &explore.PutDecisionsResponse_Result{MutualLikes: decisionResponse.MutualLikes, Replayed: decisionResponse.Replayed}
+		recipients = append(recipients, User{Id: UUID(decisionRequest.RecipientUserId)})
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.committed(ctx, UUID(actor), recipients...)
+
+	response := &explore.PutDecisionsResponse{Results: results}
+
+	return response, nil
+}
+func (s ExploreServer) GetQuota(ctx context.Context, request *explore.GetQuotaRequest) (*explore.GetQuotaResponse, error) {
+	log.Printf("GetQuota request: [id=%v]", request.UserId)
+
+	user := User{
+		Id: UUID(request.UserId),
+	}
+
+	var quota Quota
+
+	exists, err := GetQuota(ctx, s.Database, user, &quota)
+	if err != nil {
+		log.Printf("error getting quota: %v", err)
+		return nil, err
+	}
+
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "user %v not found", request.UserId)
+	}
+
+	return quota.ToResponse(), nil
+}
+func (s ExploreServer) BlockUser(ctx context.Context, request *explore.BlockUserRequest) (*explore.BlockUserResponse, error) {
+	log.Printf("BlockUser request: [from=%v] [to=%v]", request.ActorUserId, request.RecipientUserId)
+
+	if request.ActorUserId == request.RecipientUserId {
+		return nil, status.Error(codes.InvalidArgument, "users can not block themselves")
+	}
+
+	blocker := User{Id: UUID(request.ActorUserId)}
+	blocked := User{Id: UUID(request.RecipientUserId)}
+
+	tx, err := BeginTx(ctx, s.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	matchDissolved, err := s.blockUser(ctx, tx, blocker, blocked)
+	if err != nil {
+		log.Printf("error blocking user: %v", err)
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.committed(ctx, blocker.Id, blocker, blocked)
+
+	response := &explore.BlockUserResponse{MatchDissolved: matchDissolved}
+
+	return response, nil
+}
+func (s ExploreServer) UnblockUser(ctx context.Context, request *explore.BlockUserRequest) (*explore.BlockUserResponse, error) {
+	log.Printf("UnblockUser request: [from=%v] [to=%v]", request.ActorUserId, request.RecipientUserId)
+
+	blocker := User{Id: UUID(request.ActorUserId)}
+	blocked := User{Id: UUID(request.RecipientUserId)}
+
+	err := UnblockUser(ctx, s.Database, blocker, blocked)
+	if err != nil {
+		log.Printf("error unblocking user: %v", err)
+		return nil, err
+	}
+
+	s.committed(ctx, blocker.Id, blocker, blocked)
+
+	response := &explore.BlockUserResponse{MatchDissolved: false}
+
+	return response, nil
+}
+func (s ExploreServer) ReportUser(ctx context.Context, request *explore.ReportUserRequest) (*explore.ReportUserResponse, error) {
+	log.Printf("ReportUser request: [from=%v] [to=%v] [block=%v]", request.ActorUserId, request.RecipientUserId, request.Block)
+
+	if request.ActorUserId == request.RecipientUserId {
+		return nil, status.Error(codes.InvalidArgument, "users can not report themselves")
+	}
+
+	// the report and the block go in together so a report can't be saved
+	// without the block that was asked for
+	tx, err := BeginTx(ctx, s.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	report, err := InsertReport(ctx, tx, Report{
+		Reporter: UUID(request.ActorUserId),
+		Reported: UUID(request.RecipientUserId),
+		Reason:   request.Reason,
+	})
+	if err != nil {
+		log.Printf("error reporting user: %v", err)
+		return nil, err
+	}
+
+	if request.Block {
+		_, err := s.blockUser(ctx, tx, User{Id: report.Reporter}, User{Id: report.Reported})
+		if err != nil {
+			log.Printf("error blocking reported user: %v", err)
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Block {
+		s.committed(ctx, report.Reporter, User{Id: report.Reporter}, User{Id: report.Reported})
+	}
+
+	response := &explore.ReportUserResponse{ReportId: uint64(report.Id)}
+
+	return response, nil
+}
+func (s ExploreServer) Unmatch(ctx context.Context, request *explore.UnmatchRequest) (*explore.UnmatchResponse, error) {
+	log.Printf("Unmatch request: [from=%v] [to=%v]", request.ActorUserId, request.RecipientUserId)
+
+	actor := User{Id: UUID(request.ActorUserId)}
+	recipient := User{Id: UUID(request.RecipientUserId)}
+
+	tx, err := BeginTx(ctx, s.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var unmatch Unmatch
+
+	unmatched, err := UnmatchUsers(ctx, tx, actor, recipient, &unmatch)
+	if err != nil {
+		log.Printf("error unmatching users: %v", err)
+		return nil, err
+	}
+
+	if unmatched {
+		err = s.recordUnmatchEvents(ctx, tx, UnmatchPayload{
+			ActorId:     unmatch.Actor,
+			RecipientId: unmatch.Recipient,
+			UnmatchId:   unmatch.Id,
+			OccurredAt:  unmatch.CreatedAt,
+		})
+		if err != nil {
+			log.Printf("error recording unmatch events: %v", err)
+			return nil, err
+		}
+
+		err = s.Events.Publish(ctx, tx, Event{
+			Type:        EventUnmatch,
+			ActorId:     unmatch.Actor,
+			RecipientId: unmatch.Recipient,
+			CreatedAt:   unmatch.CreatedAt,
+		})
+		if err != nil {
+			log.Printf("error publishing unmatch event: %v", err)
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if unmatched {
+		s.committed(ctx, actor.Id, actor, recipient)
+	}
+
+	response := &explore.UnmatchResponse{Unmatched: unmatched}
+
+	return response, nil
+}
+func (s ExploreServer) ListCandidates(ctx context.Context, request *explore.ListCandidatesRequest) (*explore.ListCandidatesResponse, error) {
+	log.Printf("ListCandidates request: [page=%v] [ranking=%v] [id=%v]", request.GetPaginationToken(), request.GetRanking(), request.ActorUserId)
+
+	user := User{
+		Id: UUID(request.ActorUserId),
+	}
+
+	rankingName := s.DefaultRanking
+	if request.Ranking != nil {
+		rankingName = *request.Ranking
+	}
+
+	ranker, err := RankerByName(rankingName)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var caller User
+	if _, err := GetUser(ctx, s.Database, user, &caller); err != nil {
+		log.Printf("error getting caller for candidate list: %v", err)
+		return nil, err
+	}
+
+	showLikedYou := LikerVisibilityForUser(caller) == LikersFull
+
+	var after *RankedCandidate
+
+	if request.PaginationToken != nil {
+		candidate, err := parseRankingToken(ranker, *request.PaginationToken)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		after = &candidate
+	}
+
+	ranked, paginationToken, err := RankCandidates(ctx, s.Database, user, ranker, showLikedYou, after)
+	if err != nil {
+		log.Printf("error getting candidate list: %v", err)
+		return nil, err
+	}
+
+	responseCandidates := make([]*explore.ListCandidatesResponse_Candidate, len(ranked))
+
+	for i, candidate := range ranked {
+		responseCandidates[i] = &explore.ListCandidatesResponse_Candidate{
+			UserId:        string(candidate.Id),
+			UnixTimestamp: uint64(candidate.CreatedAt.Unix()),
+		}
+	}
+
+	// same as ListLikedYou, a full page means there might be more
+	var paginationTokenPtr *string
+
+	if len(responseCandidates) == PaginationSize {
+		paginationTokenPtr = &paginationToken
+	}
+
+	response := &explore.ListCandidatesResponse{
+		Candidates:          responseCandidates,
+		NextPaginationToken: paginationTokenPtr,
+	}
+
+	return response, nil
+}
+func (s ExploreServer) WatchLikes(request *explore.WatchRequest, stream grpc.ServerStreamingServer[explore.LikeEvent]) error {
+	log.Printf("WatchLikes request: [id=%v]", request.UserId)
+
+	ctx := stream.Context()
+	user := User{Id: UUID(request.UserId)}
+
+	// the tier is checked once at the start, if they upgrade while watching
+	// they need to watch again to see who liked them
+	var watcher User
+	if _, err := GetUser(ctx, s.Database, user, &watcher); err != nil {
+		log.Printf("error getting watcher: %v", err)
+		return err
+	}
+
+	visibility := LikerVisibilityForUser(watcher)
+
+	subscription := s.Events.Subscribe(user.Id)
+	defer s.Events.Unsubscribe(subscription)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("WatchLikes finished: [id=%v]", request.UserId)
+			return nil
+		case event := <-subscription.Events:
+			if event.Type != EventLike {
+				continue
+			}
+
+			liker := &explore.ListLikedYouResponse_Liker{
+				ActorId:       string(event.ActorId),
+				UnixTimestamp: uint64(event.CreatedAt.Unix()),
+			}
+
+			err := stream.Send(&explore.LikeEvent{Liker: RedactLiker(visibility, liker)})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+func (s ExploreServer) WatchMatches(request *explore.WatchRequest, stream grpc.ServerStreamingServer[explore.MatchEvent]) error {
+	log.Printf("WatchMatches request: [id=%v]", request.UserId)
+
+	ctx := stream.Context()
+	user := UUID(request.UserId)
+
+	subscription := s.Events.Subscribe(user)
+	defer s.Events.Unsubscribe(subscription)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("WatchMatches finished: [id=%v]", request.UserId)
+			return nil
+		case event := <-subscription.Events:
+			if event.Type != EventMatch {
+				continue
+			}
+
+			// both users are told about the match so send whoever isn't the watcher
+			other := event.RecipientId
+			if other == user {
+				other = event.ActorId
+			}
+
+			err := stream.Send(&explore.MatchEvent{
+				UserId:        string(other),
+				UnixTimestamp: uint64(event.CreatedAt.Unix()),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// applyDecision records a single decision as part of tx. Nothing is committed so
+// PutDecision and PutDecisions can decide what to do when it fails
+func (s ExploreServer) applyDecision(ctx context.Context, tx DBTX, request *explore.PutDecisionRequest) (*explore.PutDecisionResponse, error) {
 	decisionRequest := Decision{
 		FromUser: UUID(request.ActorUserId),
 		ToUser:   UUID(request.RecipientUserId),
 		Liked:    request.LikedRecipient,
 	}
 
-	_, err := InsertDecision(ctx, s.Database, decisionRequest)
+	// a retry with the same key gets back the original response and nothing is
+	// changed, so quota isn't taken again and no events are sent twice
+	if request.IdempotencyKey != nil {
+		key := *request.IdempotencyKey
+
+		if key == "" || len(key) > MaxIdempotencyKeyLength {
+			return nil, status.Errorf(codes.InvalidArgument, "idempotency key must be between 1 and %v characters", MaxIdempotencyKeyLength)
+		}
+
+		var record IdempotencyRecord
+
+		claimed, err := ClaimIdempotencyKey(ctx, tx, decisionRequest, key, &record)
+		if err != nil {
+			return nil, err
+		}
+
+		if !claimed {
+			if record.RecipientId != decisionRequest.ToUser || record.Liked != decisionRequest.Liked {
+				return nil, status.Errorf(codes.InvalidArgument, "idempotency key \"%v\" was already used for a different decision", key)
+			}
+
+			log.Printf("PutDecision replayed: [from=%v] [to=%v] [key=%v]", decisionRequest.FromUser, decisionRequest.ToUser, key)
+
+			return &explore.PutDecisionResponse{MutualLikes: record.MutualLikes, Replayed: true}, nil
+		}
+	}
+
+	var existingDecision Decision
+
+	decidedBefore, err := GetExistingDecision(ctx, tx, decisionRequest, &existingDecision)
 	if err != nil {
 		return nil, err
 	}
 
+	// resending the same decision shouldn't count twice towards anything
+	changed := !decidedBefore || existingDecision.Liked != decisionRequest.Liked
+
+	// whether the like being replaced was in the recipients count has to be known
+	// before it is overwritten, it may have been hidden by a block or an unmatch
+	wasCounted := false
+	if changed && decidedBefore && existingDecision.Liked {
+		wasCounted, err = IsLikeCounted(ctx, tx, User{Id: decisionRequest.FromUser}, User{Id: decisionRequest.ToUser})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// likes use up the daily quota, but only if this isn't a like the actor has
+	// already sent before otherwise resending the same like would cost twice
+	if decisionRequest.Liked {
+		if changed {
+			actor := User{Id: decisionRequest.FromUser}
+
+			taken, err := TakeLikeQuota(ctx, tx, actor)
+			if err != nil {
+				return nil, err
+			}
+
+			if !taken {
+				// no quota row to take from means there is no such user
+				var quota Quota
+				exists, err := GetQuota(ctx, tx, actor, &quota)
+				if err != nil {
+					return nil, err
+				}
+
+				if !exists {
+					return nil, status.Errorf(codes.NotFound, "user %v not found", actor.Id)
+				}
+
+				log.Printf("PutDecision over quota: [from=%v] [used=%v] [daily=%v]", actor.Id, quota.LikesUsed, quota.DailyLikes)
+				return nil, QuotaExceededError(ctx, quota)
+			}
+		}
+	}
+
+	decision, err := InsertDecision(ctx, tx, decisionRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		var actor User
+		var recipient User
+
+		actorExists, err := GetUser(ctx, tx, User{Id: decisionRequest.FromUser}, &actor)
+		if err != nil {
+			return nil, err
+		}
+
+		recipientExists, err := GetUser(ctx, tx, User{Id: decisionRequest.ToUser}, &recipient)
+		if err != nil {
+			return nil, err
+		}
+
+		// whatever the old decision added is taken back before the new one is
+		// applied, otherwise changing it back and forth keeps moving the score
+		if actorExists && recipientExists {
+			previous := existingDecision.DesirabilityDelta
+			delta := DesirabilityDelta(recipient.Desirability-previous, actor.Desirability, decisionRequest.Liked)
+
+			_, err := UpdateDesirability(ctx, tx, recipient, delta-previous)
+			if err != nil {
+				return nil, err
+			}
+
+			err = SetDecisionDesirabilityDelta(ctx, tx, decision, delta)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	mutualLike := false
+	blocked := false
+
+	// the decision is still recorded between blocked users but they are
+	// never told they matched or that they were liked, and it isn't counted
+	if changed || decisionRequest.Liked {
+		blocked, err = IsBlocked(ctx, tx, User{Id: decisionRequest.FromUser}, User{Id: decisionRequest.ToUser})
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// if this was a like then check for the same decision but from the to_user
 	// to the from_user in the original decision, if there was no like then dont bother
-	if request.LikedRecipient {
+	if decisionRequest.Liked {
 		oppositeDecision := Decision{
-			FromUser: UUID(request.RecipientUserId),
-			ToUser:   UUID(request.ActorUserId),
+			FromUser: decisionRequest.ToUser,
+			ToUser:   decisionRequest.FromUser,
 		}
 
-		exists, err := GetDecision(ctx, s.Database, oppositeDecision, &oppositeDecision)
+		exists, err := GetDecision(ctx, tx, oppositeDecision, &oppositeDecision)
 		if err != nil {
 			return nil, err
 		}
@@ -142,9 +782,189 @@ func (s ExploreServer) PutDecision(ctx context.Context, request *explore.PutDeci
 		if exists && oppositeDecision.Liked {
 			mutualLike = true
 		}
+
+		mutualLike = mutualLike && !blocked
+	}
+
+	if changed {
+		err = s.recordDecisionEvents(ctx, tx, decision, mutualLike)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// a new like adds one to the recipients cached count and a counted like
+	// turning into a pass takes one away, anything else leaves it alone
+	if changed {
+		delta := 0
+
+		if decisionRequest.Liked && !blocked {
+			delta = 1
+		} else if wasCounted {
+			delta = -1
+		}
+
+		if delta != 0 {
+			err = AdjustLikeCount(ctx, tx, User{Id: decisionRequest.ToUser}, delta)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// only new likes are published, resending the same like shouldn't notify anyone again
+	if changed && decisionRequest.Liked && !blocked {
+		err = s.Events.Publish(ctx, tx, Event{
+			Type:        EventLike,
+			ActorId:     decisionRequest.FromUser,
+			RecipientId: decisionRequest.ToUser,
+			CreatedAt:   time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if mutualLike {
+			err = s.Events.Publish(ctx, tx, Event{
+				Type:        EventMatch,
+				ActorId:     decisionRequest.FromUser,
+				RecipientId: decisionRequest.ToUser,
+				CreatedAt:   time.Now(),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if request.IdempotencyKey != nil {
+		err = SetIdempotencyResult(ctx, tx, decisionRequest, *request.IdempotencyKey, mutualLike)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	response := &explore.PutDecisionResponse{MutualLikes: mutualLike}
 
 	return response, nil
 }
+
+func (s ExploreServer) applyDecisionInSavepoint(ctx context.Context, tx DBTX, request *explore.PutDecisionRequest) (*explore.PutDecisionResponse, error) {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer savepoint.Rollback(ctx)
+
+	response, err := s.applyDecision(ctx, savepoint, request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = savepoint.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// committed is called once a change made by actor has committed. The cached likes
+// of everyone it changed are dropped and they all read from the primary for a
+// while, the actor so they see their own change and the others so a replica that
+// hasn't caught up yet doesn't put the old likes straight back in the cache
+func (s ExploreServer) committed(ctx context.Context, actor UUID, changed ...User) {
+	s.Likes.Invalidate(ctx, changed...)
+
+	users := []UUID{actor}
+	for _, user := range changed {
+		users = append(users, user.Id)
+	}
+
+	s.Reads.Wrote(users...)
+}
+
+// recordDecisionEvents writes the outbox events and webhook deliveries for a
+// decision, this has to be called with the same transaction the decision was
+// written in
+func (s ExploreServer) recordDecisionEvents(ctx context.Context, tx DBTX, decision Decision, mutualLike bool) error {
+	payload := DecisionPayload{
+		ActorId:     decision.FromUser,
+		RecipientId: decision.ToUser,
+		DecisionId:  decision.Id,
+		OccurredAt:  time.Now().UTC(),
+	}
+
+	eventType := OutboxPassRecorded
+	if decision.Liked {
+		eventType = OutboxLikeRecorded
+	}
+
+	_, err := InsertOutboxEvent(ctx, tx, eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	if mutualLike {
+		_, err := InsertOutboxEvent(ctx, tx, OutboxMatchCreated, payload)
+		if err != nil {
+			return err
+		}
+
+		err = EnqueueWebhookDeliveries(ctx, tx, OutboxMatchCreated, payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// blockUser blocks the user and if that dissolves a match it is recorded the same
+// as an unmatch, so watchers, the outbox and webhooks all hear the pair split up
+func (s ExploreServer) blockUser(ctx context.Context, tx DBTX, blocker User, blocked User) (bool, error) {
+	var unmatch Unmatch
+
+	matchDissolved, err := BlockUser(ctx, tx, blocker, blocked, &unmatch)
+	if err != nil {
+		return false, err
+	}
+
+	if !matchDissolved {
+		return false, nil
+	}
+
+	err = s.recordUnmatchEvents(ctx, tx, UnmatchPayload{
+		ActorId:     unmatch.Actor,
+		RecipientId: unmatch.Recipient,
+		UnmatchId:   unmatch.Id,
+		OccurredAt:  unmatch.CreatedAt,
+		Blocked:     true,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	err = s.Events.Publish(ctx, tx, Event{
+		Type:        EventUnmatch,
+		ActorId:     unmatch.Actor,
+		RecipientId: unmatch.Recipient,
+		CreatedAt:   unmatch.CreatedAt,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// recordUnmatchEvents is the same as recordDecisionEvents but for an unmatch
+func (s ExploreServer) recordUnmatchEvents(ctx context.Context, tx DBTX, payload UnmatchPayload) error {
+	_, err := InsertOutboxEvent(ctx, tx, OutboxUnmatchRecorded, payload)
+	if err != nil {
+		return err
+	}
+
+	return EnqueueWebhookDeliveries(ctx, tx, OutboxUnmatchRecorded, payload)
+}
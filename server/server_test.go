@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jackdelahunt/protoexplore/explore"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBlockUserRecordsUnmatch(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	blocker := User{Id: "blocker"}
+	blocked := User{Id: "blocked"}
+
+	tests := []struct {
+		name    string
+		matched bool
+	}{
+		{name: "not matched", matched: false},
+		{name: "matched", matched: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db := &fakeDB{
+				results: map[string][][]any{
+					getBlockExistsSQL:    {{false}},
+					getLikeCountedSQL:    {{false}},
+					insertUnmatchSQL:     {{7, now, blocker.Id, blocked.Id}},
+					insertOutboxEventSQL: {{int64(1)}},
+				},
+				affected: map[string]int64{},
+			}
+
+			if test.matched {
+				db.affected[dissolveMatchSQL] = 1
+			}
+
+			broker := NewLocalBroker()
+			subscription := broker.Subscribe(blocked.Id)
+
+			server := ExploreServer{Events: broker}
+
+			tx, err := BeginTx(ctx, db)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dissolved, err := server.blockUser(ctx, tx, blocker, blocked)
+			if err != nil {
+				t.Fatalf("blockUser() error = %v", err)
+			}
+
+			if dissolved != test.matched {
+				t.Errorf("blockUser() = %v, want %v", dissolved, test.matched)
+			}
+
+			if len(subscription.Events) != 0 {
+				t.Errorf("unmatch was published before the block committed")
+			}
+
+			if err := tx.Commit(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			outbox := db.ran(insertOutboxEventSQL)
+			if len(outbox) != boolToInt(test.matched) {
+				t.Fatalf("outbox events = %v, want %v", len(outbox), boolToInt(test.matched))
+			}
+
+			if !test.matched {
+				if len(subscription.Events) != 0 {
+					t.Errorf("an unmatch was published without a match")
+				}
+				return
+			}
+
+			var payload UnmatchPayload
+			if err := json.Unmarshal([]byte(outbox[0].args[1].(string)), &payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if outbox[0].args[0] != OutboxUnmatchRecorded || !payload.Blocked || payload.UnmatchId != 7 || payload.ActorId != blocker.Id {
+				t.Errorf("outbox event = %v %+v, want a blocked unmatch", outbox[0].args[0], payload)
+			}
+
+			if len(db.ran(enqueueWebhookDeliveriesSQL)) != 1 {
+				t.Errorf("webhooks weren't told about the unmatch")
+			}
+
+			select {
+			case event := <-subscription.Events:
+				if event.Type != EventUnmatch || event.ActorId != blocker.Id || event.RecipientId != blocked.Id {
+					t.Errorf("event = %+v, want an unmatch from the blocker", event)
+				}
+			default:
+				t.Errorf("unmatch wasn't published once the block committed")
+			}
+		})
+	}
+}
+
+func TestApplyDecisionLikeCount(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	from := UUID("from")
+	to := UUID("to")
+
+	tests := []struct {
+		name    string
+		before  *bool
+		liked   bool
+		counted bool
+		blocked bool
+		delta   int
+	}{
+		{name: "new like", before: nil, liked: true, delta: 1},
+		{name: "new like while blocked", before: nil, liked: true, blocked: true, delta: 0},
+		{name: "pass turned into a like", before: ptr(false), liked: true, delta: 1},
+		{name: "counted like turned into a pass", before: ptr(true), liked: false, counted: true, delta: -1},
+		{name: "like hidden by an unmatch turned into a pass", before: ptr(true), liked: false, counted: false, delta: 0},
+		{name: "same like again", before: ptr(true), liked: true, counted: true, delta: 0},
+		{name: "new pass", before: nil, liked: false, delta: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{
+				results: map[string][][]any{
+					takeLikeQuotaSQL:     {{1}},
+					insertDecisionSQL:    {{1, now, from, to, test.liked}},
+					getBlockExistsSQL:    {{test.blocked}},
+					getLikeCountedSQL:    {{test.counted}},
+					insertOutboxEventSQL: {{int64(1)}},
+				},
+			}
+
+			if test.before != nil {
+				db.results[getExistingDecisionSQL] = [][]any{{1, now, from, to, *test.before}}
+			}
+
+			server := ExploreServer{Events: NewLocalBroker()}
+
+			request := &explore.PutDecisionRequest{
+				ActorUserId:     string(from),
+				RecipientUserId: string(to),
+				LikedRecipient:  test.liked,
+			}
+
+			if _, err := server.applyDecision(context.Background(), db, request); err != nil {
+				t.Fatalf("applyDecision() error = %v", err)
+			}
+
+			adjusts := db.ran(adjustLikeCountSQL)
+
+			if test.delta == 0 {
+				if len(adjusts) != 0 {
+					t.Errorf("like count adjusted by %v, want it left alone", adjusts[0].args[1])
+				}
+				return
+			}
+
+			if len(adjusts) != 1 || !reflect.DeepEqual(adjusts[0].args, []any{to, test.delta}) {
+				t.Errorf("like count adjusted %v, want %v for the recipient", adjusts, test.delta)
+			}
+		})
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestCommitted(t *testing.T) {
+	ctx := context.Background()
+	// the replica is still behind and only has the old count
+	primary := &fakeDB{rows: [][]any{{uint64(2)}}}
+	replica := &fakeDB{rows: [][]any{{uint64(1)}}}
+
+	actor := User{Id: "actor"}
+	recipient := User{Id: "recipient"}
+	other := User{Id: "other"}
+
+	server := ExploreServer{
+		Likes: NewLikesCache(NewMemoryCache(10), time.Minute),
+		Reads: NewReadRouter(primary, replica, time.Minute),
+	}
+
+	server.committed(ctx, actor.Id, recipient)
+
+	for _, user := range []User{actor, recipient} {
+		if got := server.Reads.Read(user.Id); got != primary {
+			t.Errorf("Read(%v) = %v, want primary", user.Id, routedTo(got, primary))
+		}
+	}
+
+	if got := server.Reads.Read(other.Id); got != replica {
+		t.Errorf("Read(%v) = %v, want replica", other.Id, routedTo(got, primary))
+	}
+
+	// the recipients next read is the one that fills the cache again so it can't
+	// come from a replica that is still behind
+	count, err := server.Likes.GetLikeCount(ctx, server.Reads.Read(recipient.Id), recipient)
+	if err != nil || count != 2 {
+		t.Errorf("GetLikeCount() = %v, %v, want 2 from the primary", count, err)
+	}
+}
+
+func TestApplyDecisionDesirability(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	from := UUID("from")
+	to := UUID("to")
+
+	// just enough of the decisions and users tables for a decision to change
+	// back and forth
+	scores := map[UUID]float64{from: 1100, to: DesirabilityBaseline}
+	var decision *Decision
+
+	db := &fakeDB{
+		results: map[string][][]any{
+			takeLikeQuotaSQL:     {{1}},
+			getBlockExistsSQL:    {{false}},
+			getLikeCountedSQL:    {{false}},
+			insertOutboxEventSQL: {{int64(1)}},
+		},
+	}
+
+	db.respond = func(sql string, args []any) ([][]any, bool) {
+		switch sql {
+		case getUserSQL:
+			return [][]any{{args[0], now, "free", scores[args[0].(UUID)]}}, true
+		case getExistingDecisionSQL:
+			if decision == nil {
+				return nil, true
+			}
+
+			return [][]any{{1, now, from, to, decision.Liked, decision.DesirabilityDelta}}, true
+		case insertDecisionSQL:
+			if decision == nil {
+				decision = &Decision{}
+			}
+
+			decision.Liked = args[2].(bool)
+
+			return [][]any{{1, now, from, to, decision.Liked}}, true
+		case updateDesirabilitySQL:
+			scores[args[0].(UUID)] += args[1].(float64)
+			return [][]any{{scores[args[0].(UUID)]}}, true
+		case setDecisionDesirabilityDeltaSQL:
+			decision.DesirabilityDelta = args[2].(float64)
+			return nil, true
+		}
+
+		return nil, false
+	}
+
+	server := ExploreServer{Events: NewLocalBroker()}
+
+	decide := func(liked bool) {
+		request := &explore.PutDecisionRequest{
+			ActorUserId:     string(from),
+			RecipientUserId: string(to),
+			LikedRecipient:  liked,
+		}
+
+		if _, err := server.applyDecision(context.Background(), db, request); err != nil {
+			t.Fatalf("applyDecision(%v) error = %v", liked, err)
+		}
+	}
+
+	decide(true)
+	liked := scores[to]
+
+	if want := DesirabilityBaseline + DesirabilityDelta(DesirabilityBaseline, 1100, true); math.Abs(liked-want) > 1e-9 {
+		t.Fatalf("score after a like = %v, want %v", liked, want)
+	}
+
+	decide(false)
+
+	// the pass replaces the like rather than adding on top of it
+	if want := DesirabilityBaseline + DesirabilityDelta(DesirabilityBaseline, 1100, false); math.Abs(scores[to]-want) > 1e-9 {
+		t.Errorf("score after changing to a pass = %v, want %v", scores[to], want)
+	}
+
+	// changing it back and forth can't keep moving the score
+	for i := 0; i < 10; i++ {
+		decide(true)
+		decide(false)
+	}
+
+	decide(true)
+
+	if math.Abs(scores[to]-liked) > 1e-9 {
+		t.Errorf("score after changing back and forth = %v, want %v", scores[to], liked)
+	}
+
+	if scores[from] != 1100 {
+		t.Errorf("actor's score changed to %v", scores[from])
+	}
+}
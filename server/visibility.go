@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/jackdelahunt/protoexplore/explore"
+)
+
+// LikerVisibility is how much a user is allowed to see about the people who
+// liked them. All of the rules for this live here so the handlers only need to
+// ask for the visibility of the caller and apply it to their response
+type LikerVisibility int
+
+const (
+	LikersFull LikerVisibility = iota
+	LikersRedacted
+)
+
+const redactedTimestampPrecision uint64 = 24 * 60 * 60
+
+func LikerVisibilityForUser(user User) LikerVisibility {
+	if user.Tier == "premium" {
+		return LikersFull
+	}
+
+	// anyone not premium, including users we don't know about, gets the
+	// least amount of information
+	return LikersRedacted
+}
+
+// ApplyLikerVisibility redacts the response in place if the visibility needs it.
+// Redacted responses never have a pagination token, the token is the id of the
+// last liker so handing it out would give away who liked them. Instead the
+// total count is sent so the client can still show how many likes there are
+func ApplyLikerVisibility(visibility LikerVisibility, response *explore.ListLikedYouResponse, totalCount uint64) {
+	if visibility == LikersFull {
+		return
+	}
+
+	for i, liker := range response.Likers {
+		response.Likers[i] = RedactLiker(visibility, liker)
+	}
+
+	response.NextPaginationToken = nil
+	response.TotalCount = &totalCount
+}
+
+// RedactLiker is for places that only hand out a single liker at a time, the
+// actor id is removed and the timestamp is only kept to the day
+func RedactLiker(visibility LikerVisibility, liker *explore.ListLikedYouResponse_Liker) *explore.ListLikedYouResponse_Liker {
+	if visibility == LikersFull {
+		return liker
+	}
+
+	return &explore.ListLikedYouResponse_Liker{
+		UnixTimestamp: liker.UnixTimestamp - liker.UnixTimestamp%redactedTimestampPrecision,
+		Redacted:      true,
+	}
+}
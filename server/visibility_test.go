@@ -0,0 +1,113 @@
+package main
+
+import (
+	"github.com/jackdelahunt/protoexplore/explore"
+	"testing"
+)
+
+func TestLikerVisibilityForUser(t *testing.T) {
+	tests := []struct {
+		tier       string
+		visibility LikerVisibility
+	}{
+		{tier: "premium", visibility: LikersFull},
+		{tier: "free", visibility: LikersRedacted},
+		{tier: "", visibility: LikersRedacted},
+		{tier: "Premium", visibility: LikersRedacted},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tier, func(t *testing.T) {
+			if visibility := LikerVisibilityForUser(User{Tier: test.tier}); visibility != test.visibility {
+				t.Errorf("LikerVisibilityForUser(%q) = %v, want %v", test.tier, visibility, test.visibility)
+			}
+		})
+	}
+}
+
+func TestRedactLiker(t *testing.T) {
+	// 2024-06-15 14:30:45 UTC
+	liker := &explore.ListLikedYouResponse_Liker{ActorId: "a", UnixTimestamp: 1718461845}
+
+	tests := []struct {
+		name       string
+		visibility LikerVisibility
+		want       *explore.ListLikedYouResponse_Liker
+	}{
+		{name: "full", visibility: LikersFull, want: liker},
+		{
+			name:       "redacted",
+			visibility: LikersRedacted,
+			// start of the day so the time can't be matched up with someone's activity
+			want: &explore.ListLikedYouResponse_Liker{UnixTimestamp: 1718409600, Redacted: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := RedactLiker(test.visibility, liker)
+
+			if got.ActorId != test.want.ActorId || got.UnixTimestamp != test.want.UnixTimestamp || got.Redacted != test.want.Redacted {
+				t.Errorf("RedactLiker() = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	// the liker might be shared with a cached page so it can't be changed
+	if liker.ActorId != "a" || liker.UnixTimestamp != 1718461845 {
+		t.Errorf("RedactLiker() changed the liker it was given to %v", liker)
+	}
+}
+
+func TestApplyLikerVisibility(t *testing.T) {
+	token := "last-liker"
+
+	tests := []struct {
+		name       string
+		visibility LikerVisibility
+		redacted   bool
+	}{
+		{name: "full", visibility: LikersFull, redacted: false},
+		{name: "redacted", visibility: LikersRedacted, redacted: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := &explore.ListLikedYouResponse{
+				Likers: []*explore.ListLikedYouResponse_Liker{
+					{ActorId: "a", UnixTimestamp: 1718461845},
+					{ActorId: "b", UnixTimestamp: 1718409600},
+				},
+				NextPaginationToken: &token,
+			}
+
+			ApplyLikerVisibility(test.visibility, response, 7)
+
+			if len(response.Likers) != 2 {
+				t.Fatalf("likers = %v, want both kept", response.Likers)
+			}
+
+			for _, liker := range response.Likers {
+				if liker.Redacted != test.redacted || (liker.ActorId == "") != test.redacted {
+					t.Errorf("liker = %v, want redacted %v", liker, test.redacted)
+				}
+			}
+
+			if !test.redacted {
+				if response.NextPaginationToken == nil || response.TotalCount != nil {
+					t.Errorf("full response changed: token %v count %v", response.NextPaginationToken, response.TotalCount)
+				}
+				return
+			}
+
+			// the token is the id of the last liker so it would give them away
+			if response.NextPaginationToken != nil {
+				t.Errorf("redacted response kept the pagination token %v", *response.NextPaginationToken)
+			}
+
+			if response.TotalCount == nil || *response.TotalCount != 7 {
+				t.Errorf("total count = %v, want 7", response.TotalCount)
+			}
+		})
+	}
+}
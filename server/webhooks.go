@@ -0,0 +1,541 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"io"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed queries/insert_webhook_subscription.sql
+var insertWebhookSubscriptionSQL string
+
+//go:embed queries/get_webhook_subscriptions.sql
+var getWebhookSubscriptionsSQL string
+
+//go:embed queries/delete_webhook_subscription.sql
+var deleteWebhookSubscriptionSQL string
+
+//go:embed queries/abandon_webhook_deliveries.sql
+var abandonWebhookDeliveriesSQL string
+
+//go:embed queries/enqueue_webhook_deliveries.sql
+var enqueueWebhookDeliveriesSQL string
+
+//go:embed queries/claim_webhook_deliveries.sql
+var claimWebhookDeliveriesSQL string
+
+//go:embed queries/insert_webhook_attempt.sql
+var insertWebhookAttemptSQL string
+
+//go:embed queries/mark_webhook_delivered.sql
+var markWebhookDeliveredSQL string
+
+//go:embed queries/mark_webhook_failed.sql
+var markWebhookFailedSQL string
+
+//go:embed queries/get_webhook_deliveries.sql
+var getWebhookDeliveriesSQL string
+
+//go:embed queries/get_webhook_attempts.sql
+var getWebhookAttemptsSQL string
+
+//go:embed queries/replay_webhook_deliveries.sql
+var replayWebhookDeliveriesSQL string
+
+// WebhookEventTypes are the only event types that can be subscribed to
+var WebhookEventTypes = []string{OutboxMatchCreated, OutboxUnmatchRecorded}
+
+const (
+	WebhookSignatureHeader = "X-Explore-Signature"
+	WebhookTimestampHeader = "X-Explore-Timestamp"
+	WebhookEventHeader     = "X-Explore-Event"
+	WebhookDeliveryHeader  = "X-Explore-Delivery"
+)
+
+type WebhookSubscription struct {
+	Id         int
+	CreatedAt  time.Time
+	Url        string
+	Secret     string
+	EventTypes []string
+}
+
+type WebhookDelivery struct {
+	Id             int64
+	CreatedAt      time.Time
+	SubscriptionId int
+	EventType      string
+	Payload        json.RawMessage
+	Attempts       int
+	LastStatusCode *int
+	LastError      *string
+	DeliveredAt    *time.Time
+	FailedAt       *time.Time
+
+	// only set when the delivery is being sent
+	Url    string
+	Secret string
+
+	// only set when listing deliveries
+	AttemptLog []WebhookAttempt
+}
+
+type WebhookAttempt struct {
+	DeliveryId  int64
+	AttemptedAt time.Time
+	StatusCode  *int
+	Error       *string
+	DurationMs  int
+}
+
+func InsertWebhookSubscription(ctx context.Context, db DBTX, subscription WebhookSubscription) (WebhookSubscription, error) {
+	var newSubscription WebhookSubscription
+
+	err := db.
+		QueryRow(ctx, insertWebhookSubscriptionSQL, subscription.Url, subscription.Secret, subscription.EventTypes).
+		Scan(&newSubscription.Id, &newSubscription.CreatedAt, &newSubscription.Url, &newSubscription.EventTypes)
+	if err != nil {
+		return WebhookSubscription{}, err
+	}
+
+	return newSubscription, nil
+}
+
+func GetWebhookSubscriptions(ctx context.Context, db DBTX) ([]WebhookSubscription, error) {
+	rows, err := db.Query(ctx, getWebhookSubscriptionsSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var subscriptions []WebhookSubscription
+
+	for rows.Next() {
+		var s WebhookSubscription
+		if err := rows.Scan(&s.Id, &s.CreatedAt, &s.Url, &s.EventTypes); err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription deactivates the subscription and gives up on anything
+// still waiting to be sent to it
+func DeleteWebhookSubscription(ctx context.Context, db DBTX, id int) (bool, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, deleteWebhookSubscriptionSQL, id)
+	if err != nil {
+		return false, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(ctx, abandonWebhookDeliveriesSQL, id)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// EnqueueWebhookDeliveries creates a delivery for every subscription to the event
+// type, like the outbox this needs to be called with the transaction that made
+// the change so the deliveries only exist if it commits
+func EnqueueWebhookDeliveries(ctx context.Context, db DBTX, eventType string, payload any) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, enqueueWebhookDeliveriesSQL, eventType, string(encoded))
+	return err
+}
+
+// GetWebhookDeliveries gets the most recent deliveries along with every attempt
+// made for each of them
+func GetWebhookDeliveries(ctx context.Context, db DBTX, failedOnly bool, limit int) ([]WebhookDelivery, error) {
+	rows, err := db.Query(ctx, getWebhookDeliveriesSQL, failedOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	var ids []int64
+
+	for rows.Next() {
+		var d WebhookDelivery
+		if err := rows.Scan(&d.Id, &d.CreatedAt, &d.SubscriptionId, &d.EventType, &d.Attempts, &d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.FailedAt); err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, d)
+		ids = append(ids, d.Id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows.Close()
+
+	attempts, err := getWebhookAttempts(ctx, db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range deliveries {
+		deliveries[i].AttemptLog = attempts[deliveries[i].Id]
+	}
+
+	return deliveries, nil
+}
+
+func getWebhookAttempts(ctx context.Context, db DBTX, deliveryIds []int64) (map[int64][]WebhookAttempt, error) {
+	rows, err := db.Query(ctx, getWebhookAttemptsSQL, deliveryIds)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	attempts := make(map[int64][]WebhookAttempt)
+
+	for rows.Next() {
+		var a WebhookAttempt
+		if err := rows.Scan(&a.DeliveryId, &a.AttemptedAt, &a.StatusCode, &a.Error, &a.DurationMs); err != nil {
+			return nil, err
+		}
+
+		attempts[a.DeliveryId] = append(attempts[a.DeliveryId], a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func ReplayWebhookDeliveries(ctx context.Context, db DBTX, ids []int64, allFailed bool) (int64, error) {
+	tag, err := db.Exec(ctx, replayWebhookDeliveriesSQL, ids, allFailed)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// SignWebhook signs the timestamp and body together so a request can't be
+// replayed later with a new timestamp
+func SignWebhook(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyWebhook(secret string, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhook(secret, timestamp, body)), []byte(signature))
+}
+
+type webhookMessage struct {
+	Id        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// WebhookDispatcher sends waiting deliveries, it works the same way as the
+// OutboxRelay except every attempt is logged and a delivery that runs out of
+// attempts is marked as failed so it can be replayed later
+type WebhookDispatcher struct {
+	Client       *http.Client
+	BatchSize    int
+	PollInterval time.Duration
+	MaxAttempts  int
+	RetryDelay   time.Duration
+	MaxDelay     time.Duration
+	ClaimFor     time.Duration
+
+	Connect func(ctx context.Context) (*pgx.Conn, error)
+}
+
+func NewWebhookDispatcher(connect func(ctx context.Context) (*pgx.Conn, error)) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		Client:       &http.Client{Timeout: 10 * time.Second},
+		BatchSize:    20,
+		PollInterval: 1 * time.Second,
+		MaxAttempts:  8,
+		RetryDelay:   2 * time.Second,
+		MaxDelay:     30 * time.Minute,
+		ClaimFor:     10 * time.Minute,
+		Connect:      connect,
+	}
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := d.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("webhook dispatcher stopped, retrying in %v: %v", d.PollInterval, err)
+		time.Sleep(d.PollInterval)
+	}
+}
+
+func (d *WebhookDispatcher) run(ctx context.Context) error {
+	conn, err := d.Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close(context.Background())
+
+	log.Printf("webhook dispatcher started")
+
+	for {
+		sent, err := d.DispatchBatch(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if sent == d.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.PollInterval):
+		}
+	}
+}
+
+// DispatchBatch sends a single batch of deliveries, returning how many were
+// attempted. Like RelayBatch the batch is claimed up front and each delivery is
+// recorded in its own transaction once it is sent, nothing is held open while
+// waiting on a slow endpoint
+func (d *WebhookDispatcher) DispatchBatch(ctx context.Context, db DBTX) (int, error) {
+	deliveries, err := d.claim(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		start := time.Now()
+		statusCode, sendErr := d.send(ctx, delivery)
+		durationMs := time.Since(start).Milliseconds()
+
+		err := d.record(ctx, db, delivery, statusCode, sendErr, durationMs)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// record logs the attempt and marks the delivery as delivered or failed together
+func (d *WebhookDispatcher) record(ctx context.Context, db DBTX, delivery WebhookDelivery, statusCode *int, sendErr error, durationMs int64) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var errorMessage *string
+	if sendErr != nil {
+		message := sendErr.Error()
+		errorMessage = &message
+	}
+
+	_, err = tx.Exec(ctx, insertWebhookAttemptSQL, delivery.Id, statusCode, errorMessage, durationMs)
+	if err != nil {
+		return err
+	}
+
+	if sendErr == nil {
+		_, err := tx.Exec(ctx, markWebhookDeliveredSQL, delivery.Id, statusCode)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit(ctx)
+	}
+
+	var failed bool
+
+	delay := d.retryDelay(delivery.Attempts)
+
+	err = tx.
+		QueryRow(ctx, markWebhookFailedSQL, delivery.Id, statusCode, sendErr.Error(), delay.Seconds(), d.MaxAttempts).
+		Scan(&failed)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	if failed {
+		log.Printf("webhook delivery failed for good: [id=%v] [url=%v] [attempts=%v]: %v", delivery.Id, delivery.Url, delivery.Attempts+1, sendErr)
+	} else {
+		log.Printf("webhook delivery failed, retrying in %v: [id=%v] [url=%v]: %v", delay, delivery.Id, delivery.Url, sendErr)
+	}
+
+	return nil
+}
+
+// send POSTs the delivery, the status code is nil if there was no response
+func (d *WebhookDispatcher) send(ctx context.Context, delivery WebhookDelivery) (*int, error) {
+	body, err := json.Marshal(webhookMessage{
+		Id:        delivery.Id,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Payload:   delivery.Payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WebhookEventHeader, delivery.EventType)
+	request.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.Id, 10))
+	request.Header.Set(WebhookTimestampHeader, timestamp)
+	request.Header.Set(WebhookSignatureHeader, SignWebhook(delivery.Secret, timestamp, body))
+
+	response, err := d.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	statusCode := response.StatusCode
+
+	if statusCode < 200 || statusCode >= 300 {
+		return &statusCode, fmt.Errorf("%v responded with %v", delivery.Url, response.Status)
+	}
+
+	return &statusCode, nil
+}
+
+func (d *WebhookDispatcher) claim(ctx context.Context, db DBTX) ([]WebhookDelivery, error) {
+	rows, err := db.Query(ctx, claimWebhookDeliveriesSQL, d.BatchSize, d.ClaimFor.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+
+	for rows.Next() {
+		var w WebhookDelivery
+		if err := rows.Scan(&w.Id, &w.CreatedAt, &w.EventType, &w.Payload, &w.Attempts, &w.Url, &w.Secret); err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].Id < deliveries[j].Id })
+
+	return deliveries, nil
+}
+
+func (d *WebhookDispatcher) retryDelay(attempts int) time.Duration {
+	delay := float64(d.RetryDelay) * math.Pow(2, float64(attempts))
+	return time.Duration(math.Min(delay, float64(d.MaxDelay)))
+}
+
+// WebhookReceiver is a stand-in for a partner service so webhooks can be tried
+// out locally. It checks the signature of everything it receives and prints it,
+// FailRate is the chance of responding with a 500 to see the retries happen
+type WebhookReceiver struct {
+	Secret   string
+	FailRate float64
+}
+
+func (r WebhookReceiver) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timestamp := request.Header.Get(WebhookTimestampHeader)
+	signature := request.Header.Get(WebhookSignatureHeader)
+
+	if !VerifyWebhook(r.Secret, timestamp, body, signature) {
+		log.Printf("rejected webhook with bad signature: [delivery=%v]", request.Header.Get(WebhookDeliveryHeader))
+		http.Error(writer, "bad signature", http.StatusUnauthorized)
+		return
+	}
+
+	if rand.Float64() < r.FailRate {
+		log.Printf("failing webhook on purpose: [delivery=%v]", request.Header.Get(WebhookDeliveryHeader))
+		http.Error(writer, "failed on purpose", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("received webhook: [event=%v] [delivery=%v] %s", request.Header.Get(WebhookEventHeader), request.Header.Get(WebhookDeliveryHeader), body)
+
+	writer.WriteHeader(http.StatusNoContent)
+}
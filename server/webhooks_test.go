@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := SignWebhook("secret", "1700000000", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		signature string
+		valid     bool
+	}{
+		{name: "signed", secret: "secret", timestamp: "1700000000", body: body, signature: signature, valid: true},
+		{name: "wrong secret", secret: "other", timestamp: "1700000000", body: body, signature: signature},
+		{name: "new timestamp", secret: "secret", timestamp: "1700000001", body: body, signature: signature},
+		{name: "changed body", secret: "secret", timestamp: "1700000000", body: []byte(`{"id":2}`), signature: signature},
+		{name: "no signature", secret: "secret", timestamp: "1700000000", body: body, signature: ""},
+		{name: "signature without prefix", secret: "secret", timestamp: "1700000000", body: body, signature: signature[len("sha256="):]},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			valid := VerifyWebhook(test.secret, test.timestamp, test.body, test.signature)
+			if valid != test.valid {
+				t.Errorf("VerifyWebhook() = %v, want %v", valid, test.valid)
+			}
+		})
+	}
+}
+
+func TestWebhookSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "ok", statusCode: http.StatusOK},
+		{name: "no content", statusCode: http.StatusNoContent},
+		{name: "server error", statusCode: http.StatusInternalServerError, wantErr: true},
+		{name: "not found", statusCode: http.StatusNotFound, wantErr: true},
+		{name: "not modified", statusCode: http.StatusNotModified, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var received *http.Request
+			var body []byte
+
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				received = request
+				body, _ = io.ReadAll(request.Body)
+				writer.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			dispatcher := NewWebhookDispatcher(nil)
+			delivery := WebhookDelivery{
+				Id:        7,
+				CreatedAt: time.Now(),
+				EventType: OutboxMatchCreated,
+				Payload:   json.RawMessage(`{"actor_id":"a"}`),
+				Url:       server.URL,
+				Secret:    "secret",
+			}
+
+			statusCode, err := dispatcher.send(context.Background(), delivery)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("send() error = %v, want error %v", err, test.wantErr)
+			}
+
+			if statusCode == nil || *statusCode != test.statusCode {
+				t.Fatalf("send() status = %v, want %v", statusCode, test.statusCode)
+			}
+
+			if received.Method != http.MethodPost {
+				t.Errorf("method = %v, want POST", received.Method)
+			}
+
+			if got := received.Header.Get(WebhookEventHeader); got != OutboxMatchCreated {
+				t.Errorf("event header = %v, want %v", got, OutboxMatchCreated)
+			}
+
+			if got := received.Header.Get(WebhookDeliveryHeader); got != strconv.FormatInt(delivery.Id, 10) {
+				t.Errorf("delivery header = %v, want %v", got, delivery.Id)
+			}
+
+			timestamp := received.Header.Get(WebhookTimestampHeader)
+			if !VerifyWebhook("secret", timestamp, body, received.Header.Get(WebhookSignatureHeader)) {
+				t.Errorf("signature does not verify for the body that was sent")
+			}
+
+			var message webhookMessage
+			if err := json.Unmarshal(body, &message); err != nil {
+				t.Fatalf("body is not a webhook message: %v", err)
+			}
+
+			if message.Id != delivery.Id || message.Type != delivery.EventType || string(message.Payload) != string(delivery.Payload) {
+				t.Errorf("message = %+v, want the delivery", message)
+			}
+		})
+	}
+}
+
+func TestWebhookSendUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	dispatcher := NewWebhookDispatcher(nil)
+
+	statusCode, err := dispatcher.send(context.Background(), WebhookDelivery{Id: 1, Url: url, Secret: "secret"})
+	if err == nil {
+		t.Fatalf("send() to a closed server should fail")
+	}
+
+	if statusCode != nil {
+		t.Errorf("send() status = %v, want nil when there was no response", *statusCode)
+	}
+}
+
+func TestWebhookReceiver(t *testing.T) {
+	tests := []struct {
+		name           string
+		receiverSecret string
+		failRate       float64
+		statusCode     int
+	}{
+		{name: "accepted", receiverSecret: "secret", statusCode: http.StatusNoContent},
+		{name: "bad signature", receiverSecret: "other", statusCode: http.StatusUnauthorized},
+		{name: "failing on purpose", receiverSecret: "secret", failRate: 1, statusCode: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(WebhookReceiver{Secret: test.receiverSecret, FailRate: test.failRate})
+			defer server.Close()
+
+			dispatcher := NewWebhookDispatcher(nil)
+
+			statusCode, err := dispatcher.send(context.Background(), WebhookDelivery{
+				Id:        1,
+				EventType: OutboxUnmatchRecorded,
+				Payload:   json.RawMessage(`{}`),
+				Url:       server.URL,
+				Secret:    "secret",
+			})
+
+			if statusCode == nil || *statusCode != test.statusCode {
+				t.Fatalf("send() status = %v, want %v", statusCode, test.statusCode)
+			}
+
+			if (err != nil) != (test.statusCode >= 300) {
+				t.Errorf("send() error = %v for status %v", err, test.statusCode)
+			}
+		})
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	dispatcher := &WebhookDispatcher{RetryDelay: 2 * time.Second, MaxDelay: time.Minute}
+
+	tests := []struct {
+		attempts int
+		delay    time.Duration
+	}{
+		{attempts: 0, delay: 2 * time.Second},
+		{attempts: 1, delay: 4 * time.Second},
+		{attempts: 2, delay: 8 * time.Second},
+		{attempts: 4, delay: 32 * time.Second},
+		{attempts: 5, delay: time.Minute},
+		{attempts: 100, delay: time.Minute},
+	}
+
+	for _, test := range tests {
+		t.Run(strconv.Itoa(test.attempts), func(t *testing.T) {
+			if delay := dispatcher.retryDelay(test.attempts); delay != test.delay {
+				t.Errorf("retryDelay(%v) = %v, want %v", test.attempts, delay, test.delay)
+			}
+		})
+	}
+}
+
+func TestDispatchBatch(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	// the path of the url is the status code the endpoint responds with
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		statusCode, _ := strconv.Atoi(request.URL.Path[1:])
+		writer.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	delivery := func(id int64, statusCode int) []any {
+		return []any{id, now, OutboxMatchCreated, []byte(`{}`), 0, server.URL + "/" + strconv.Itoa(statusCode), "secret"}
+	}
+
+	db := &fakeDB{
+		results: map[string][][]any{
+			claimWebhookDeliveriesSQL: {delivery(2, http.StatusInternalServerError), delivery(1, http.StatusOK), delivery(3, http.StatusNoContent)},
+			markWebhookFailedSQL:      {{false}},
+		},
+	}
+
+	dispatcher := NewWebhookDispatcher(nil)
+
+	sent, err := dispatcher.DispatchBatch(context.Background(), db)
+	if err != nil {
+		t.Fatalf("DispatchBatch() error = %v", err)
+	}
+
+	if sent != 3 {
+		t.Errorf("DispatchBatch() = %v, want 3", sent)
+	}
+
+	claims := db.ran(claimWebhookDeliveriesSQL)
+	if len(claims) != 1 || !reflect.DeepEqual(claims[0].args, []any{dispatcher.BatchSize, dispatcher.ClaimFor.Seconds()}) {
+		t.Errorf("claim ran %v, want once for a batch", claims)
+	}
+
+	var attempted []int64
+	for _, query := range db.ran(insertWebhookAttemptSQL) {
+		attempted = append(attempted, query.args[0].(int64))
+	}
+
+	if !reflect.DeepEqual(attempted, []int64{1, 2, 3}) {
+		t.Errorf("attempts logged for %v, want every delivery oldest first", attempted)
+	}
+
+	var delivered []int64
+	for _, query := range db.ran(markWebhookDeliveredSQL) {
+		delivered = append(delivered, query.args[0].(int64))
+	}
+
+	if !reflect.DeepEqual(delivered, []int64{1, 3}) {
+		t.Errorf("marked delivered %v, want [1 3]", delivered)
+	}
+
+	failed := db.ran(markWebhookFailedSQL)
+	if len(failed) != 1 || failed[0].args[0] != int64(2) {
+		t.Errorf("marked failed %v, want 2", failed)
+	}
+
+	// each delivery is recorded on its own, a later one failing to be recorded
+	// can't undo the ones that were already sent
+	if db.commits != 3 {
+		t.Errorf("commits = %v, want one per delivery", db.commits)
+	}
+}
+
+func TestDeleteWebhookSubscription(t *testing.T) {
+	tests := []struct {
+		name    string
+		active  bool
+		deleted bool
+	}{
+		{name: "active", active: true, deleted: true},
+		{name: "already deleted", active: false, deleted: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := &fakeDB{affected: map[string]int64{}}
+
+			if test.active {
+				db.affected[deleteWebhookSubscriptionSQL] = 1
+			}
+
+			deleted, err := DeleteWebhookSubscription(context.Background(), db, 4)
+			if err != nil {
+				t.Fatalf("DeleteWebhookSubscription() error = %v", err)
+			}
+
+			if deleted != test.deleted {
+				t.Errorf("DeleteWebhookSubscription() = %v, want %v", deleted, test.deleted)
+			}
+
+			// anything still waiting would never be sent so it is given up on
+			abandoned := db.ran(abandonWebhookDeliveriesSQL)
+			if test.deleted && (len(abandoned) != 1 || !reflect.DeepEqual(abandoned[0].args, []any{4}) || db.commits != 1) {
+				t.Errorf("abandon ran %v with %v commits, want once for the subscription", abandoned, db.commits)
+			}
+
+			if !test.deleted && len(abandoned) != 0 {
+				t.Errorf("abandoned deliveries for a subscription that wasn't deleted")
+			}
+		})
+	}
+}